@@ -5,19 +5,43 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"ecom/internal/config"
 	"ecom/internal/database"
+	"ecom/migrations"
 	"ecom/pkg/migrate"
 )
 
 func main() {
 	var (
-		command = flag.String("command", "up", "Migration command: up, down, status")
-		dir     = flag.String("dir", "migrations", "Migrations directory")
+		command   = flag.String("command", "up", "Migration command: up, down, goto, redo, status, diff, create")
+		dir       = flag.String("dir", "", "Migrations directory (default: migrations embedded in the binary)")
+		steps     = flag.Int("steps", 1, "Number of migrations to roll back (down)")
+		version   = flag.String("version", "", "Target version (goto); 0 rolls back everything")
+		name      = flag.String("name", "", "Name of the migration to scaffold (create)")
+		appliedBy = flag.String("applied-by", "", "Identity recorded with applied migrations (default: user@host)")
+		schema    = flag.String("schema", "public", "Schema to compare against the applied migrations (diff)")
+		dryRun    = flag.Bool("dry-run", false, "Print the migrations up/down/goto/redo would run, with their contents, without executing them")
+		backfill  = flag.Bool("backfill-checksums", false, "Record the current file's checksum for applied migrations that have none (applied by an older runner)")
 	)
 	flag.Parse()
 
+	// Scaffolding does not need a database connection
+	if *command == "create" {
+		createDir := *dir
+		if createDir == "" {
+			createDir = "migrations"
+		}
+		upPath, downPath, err := migrate.Create(createDir, *name, time.Now())
+		if err != nil {
+			log.Fatalf("Create failed: %v", err)
+		}
+		fmt.Printf("📝 Created %s\n", upPath)
+		fmt.Printf("📝 Created %s\n", downPath)
+		return
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -25,13 +49,21 @@ func main() {
 	}
 
 	// Connect to database
-	if err := database.Connect(cfg); err != nil {
+	db, err := database.Connect(cfg)
+	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer database.Close()
+	defer db.Close()
 
-	// Create migration runner
-	runner := migrate.NewMigrationRunner(database.GetDB(), *dir)
+	// Create migration runner (embedded migrations unless -dir or MIGRATIONS_DIR is given)
+	migrationsDir := *dir
+	if migrationsDir == "" {
+		migrationsDir = cfg.Migrations.Dir
+	}
+	runner := migrations.NewRunner(db, migrationsDir)
+	runner.SetAppliedBy(*appliedBy)
+	runner.SetDryRun(*dryRun)
+	runner.SetBackfillChecksums(*backfill)
 
 	// Execute command
 	switch *command {
@@ -40,21 +72,44 @@ func main() {
 			log.Fatalf("Migration failed: %v", err)
 		}
 	case "down":
-		if err := runner.Down(); err != nil {
+		if err := runner.Down(*steps); err != nil {
 			log.Fatalf("Rollback failed: %v", err)
 		}
+	case "goto":
+		if *version == "" {
+			log.Fatal("goto requires -version")
+		}
+		if err := runner.Goto(*version); err != nil {
+			log.Fatalf("Goto failed: %v", err)
+		}
+	case "redo":
+		if err := runner.Redo(); err != nil {
+			log.Fatalf("Redo failed: %v", err)
+		}
 	case "status":
 		if err := runner.Status(); err != nil {
 			log.Fatalf("Status check failed: %v", err)
 		}
+	case "diff":
+		report, err := runner.Diff(*schema)
+		if err != nil {
+			log.Fatalf("Diff failed: %v", err)
+		}
+		report.Print()
+		if report.HasDrift() {
+			os.Exit(1)
+		}
 	default:
-		fmt.Println("Usage: go run cmd/migrate/main.go -command=<up|down|status>")
+		fmt.Println("Usage: go run cmd/migrate/main.go -command=<up|down|goto|redo|status|diff|create>")
 		fmt.Println("\nCommands:")
-		fmt.Println("  up     - Run all pending migrations")
-		fmt.Println("  down   - Rollback last migration")
-		fmt.Println("  status - Show migration status")
+		fmt.Println("  up                  - Run all pending migrations")
+		fmt.Println("  down -steps=N       - Roll back the last N migrations (default 1)")
+		fmt.Println("  goto -version=X     - Migrate up or down to version X (0 = empty)")
+		fmt.Println("  redo                - Roll back and re-apply the last migration")
+		fmt.Println("  status              - Show migration status")
+		fmt.Println("  diff [-schema=S]    - Compare the live schema with the applied migrations")
+		fmt.Println("  create -name=NAME   - Scaffold a timestamped up/down migration pair")
+		fmt.Println("\nAdd -dry-run to up, down, goto or redo to print the plan without executing it.")
 		os.Exit(1)
 	}
 }
-
-
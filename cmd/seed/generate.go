@@ -0,0 +1,639 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// profile sizes a generated dataset
+type profile struct {
+	RootCategories int
+	Categories     int
+	Products       int
+	Customers      int
+	Orders         int
+	// ReviewRate is the chance that a delivered order item gets reviewed
+	ReviewRate float64
+}
+
+var profiles = map[string]profile{
+	"small":  {RootCategories: 5, Categories: 30, Products: 1_000, Customers: 500, Orders: 2_000, ReviewRate: 0.30},
+	"medium": {RootCategories: 10, Categories: 150, Products: 20_000, Customers: 20_000, Orders: 100_000, ReviewRate: 0.20},
+	"large":  {RootCategories: 20, Categories: 500, Products: 100_000, Customers: 200_000, Orders: 1_000_000, ReviewRate: 0.10},
+}
+
+// Each entity kind draws from its own random stream so that, for example,
+// the items of order N are the same no matter how many products were generated
+// before them, and a table can be re-derived without holding it in memory.
+const (
+	streamCategories uint64 = iota + 1
+	streamProducts
+	streamCustomers
+	streamOrders
+)
+
+// generationEpoch anchors all generated timestamps so output is reproducible
+var generationEpoch = time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// generator deterministically produces a dataset for one profile and seed
+type generator struct {
+	profile profile
+	seed    uint64
+	prefix  string
+
+	// ID offsets so generated rows never collide with existing data
+	categoryBase int64
+	productBase  int64
+	customerBase int64
+	addressBase  int64
+	orderBase    int64
+
+	products []genProduct
+}
+
+type genProduct struct {
+	ID    int64
+	SKU   string
+	Name  string
+	Price int64 // cents
+}
+
+type genOrderItem struct {
+	ProductID int64
+	SKU       string
+	Name      string
+	Quantity  int
+	UnitPrice int64
+	Discount  int64
+}
+
+type genOrder struct {
+	ID              int64
+	Number          string
+	CustomerID      int64
+	AddressID       int64
+	Status          string
+	Items           []genOrderItem
+	Subtotal        int64
+	Tax             int64
+	Shipping        int64
+	Discount        int64
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	CancelledAt     *time.Time
+	CancelledReason *string
+}
+
+func (g *generator) rng(stream uint64, index int) *rand.Rand {
+	return rand.New(rand.NewPCG(g.seed, stream<<40|uint64(index)))
+}
+
+// generateData creates a synthetic dataset with COPY inside one transaction
+func generateData(db *sql.DB, profileName string, seed uint64) error {
+	p, ok := profiles[profileName]
+	if !ok {
+		return fmt.Errorf("unknown profile %q (expected small, medium or large)", profileName)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	g := &generator{profile: p, seed: seed, prefix: fmt.Sprintf("gen%d", seed)}
+
+	bases := []struct {
+		table string
+		base  *int64
+	}{
+		{"categories", &g.categoryBase},
+		{"products", &g.productBase},
+		{"customers", &g.customerBase},
+		{"customer_addresses", &g.addressBase},
+		{"orders", &g.orderBase},
+	}
+	for _, b := range bases {
+		if err := tx.QueryRow(fmt.Sprintf("SELECT COALESCE(MAX(id), 0) FROM %s", b.table)).Scan(b.base); err != nil {
+			return fmt.Errorf("failed to read max id of %s: %w", b.table, err)
+		}
+	}
+
+	// Ratings are recomputed in one statement at the end instead of per review row
+	if _, err := tx.Exec("ALTER TABLE product_reviews DISABLE TRIGGER update_rating_on_review"); err != nil {
+		return fmt.Errorf("failed to disable review trigger: %w", err)
+	}
+
+	steps := []struct {
+		name string
+		run  func(*sql.Tx) (int, error)
+	}{
+		{"categories", g.copyCategories},
+		{"products", g.copyProducts},
+		{"customers", g.copyCustomers},
+		{"customer_addresses", g.copyAddresses},
+		{"orders", g.copyOrders},
+		{"order_items", g.copyOrderItems},
+		{"payments", g.copyPayments},
+		{"product_reviews", g.copyReviews},
+	}
+
+	for _, step := range steps {
+		start := time.Now()
+		count, err := step.run(tx)
+		if err != nil {
+			return fmt.Errorf("failed to generate %s: %w", step.name, err)
+		}
+		fmt.Printf("    ✓ %-20s %9d rows in %v\n", step.name, count, time.Since(start).Round(time.Millisecond))
+	}
+
+	finalize := []string{
+		`UPDATE products p
+		 SET rating_average = r.avg_rating, rating_count = r.review_count
+		 FROM (
+			SELECT product_id, AVG(rating)::DECIMAL(3,2) AS avg_rating, COUNT(*) AS review_count
+			FROM product_reviews
+			WHERE deleted_at IS NULL AND is_approved = true
+			GROUP BY product_id
+		 ) r
+		 WHERE p.id = r.product_id`,
+		"ALTER TABLE product_reviews ENABLE TRIGGER update_rating_on_review",
+	}
+	for _, table := range []string{"categories", "products", "customers", "customer_addresses", "orders", "order_items", "payments", "product_reviews"} {
+		finalize = append(finalize, fmt.Sprintf(
+			"SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), COALESCE((SELECT MAX(id) FROM %[1]s), 0) + 1, false)", table))
+	}
+	for _, query := range finalize {
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("failed to finalize generated data: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit generated data: %w", err)
+	}
+	return nil
+}
+
+// copyRows streams rows produced by fill into table using COPY
+func copyRows(tx *sql.Tx, table string, columns []string, fill func(emit func(values ...any) error) error) (int, error) {
+	stmt, err := tx.Prepare(pq.CopyIn(table, columns...))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	count := 0
+	emit := func(values ...any) error {
+		count++
+		_, err := stmt.Exec(values...)
+		return err
+	}
+
+	if err := fill(emit); err != nil {
+		return 0, err
+	}
+	if _, err := stmt.Exec(); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (g *generator) copyCategories(tx *sql.Tx) (int, error) {
+	columns := []string{"id", "name", "slug", "description", "parent_id", "image_url", "is_active", "sort_order", "created_at", "updated_at"}
+
+	return copyRows(tx, "categories", columns, func(emit func(...any) error) error {
+		for i := 0; i < g.profile.Categories; i++ {
+			r := g.rng(streamCategories, i)
+			id := g.categoryBase + int64(i) + 1
+
+			var parentID any
+			name := pick(r, categoryNouns)
+			if i >= g.profile.RootCategories {
+				// Parents always precede children, so the tree is at most a few levels deep
+				parentID = g.categoryBase + int64(r.IntN(i)) + 1
+				name = pick(r, adjectives) + " " + name
+			}
+			createdAt := randomTime(r, 900)
+
+			if err := emit(
+				id, name, fmt.Sprintf("%s-c%d", g.prefix, i+1),
+				"Generated category "+name, parentID,
+				fmt.Sprintf("https://cdn.example.com/categories/%d.jpg", i+1),
+				r.Float64() > 0.05, i%50, createdAt, createdAt,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (g *generator) copyProducts(tx *sql.Tx) (int, error) {
+	columns := []string{"id", "sku", "name", "slug", "description", "short_description", "category_id", "status",
+		"price", "compare_at_price", "cost_price", "stock_quantity", "low_stock_threshold", "weight_kg",
+		"dimensions_cm", "barcode", "manufacturer", "brand", "view_count", "is_featured", "meta_title",
+		"meta_description", "created_at", "updated_at"}
+
+	g.products = make([]genProduct, 0, g.profile.Products)
+
+	return copyRows(tx, "products", columns, func(emit func(...any) error) error {
+		for i := 0; i < g.profile.Products; i++ {
+			r := g.rng(streamProducts, i)
+			id := g.productBase + int64(i) + 1
+			brand := pick(r, brands)
+			name := fmt.Sprintf("%s %s %s %d", brand, pick(r, adjectives), pick(r, productNouns), 100+r.IntN(900))
+			sku := fmt.Sprintf("%s-P%07d", strings.ToUpper(g.prefix), i+1)
+
+			// Log-normal-ish price spread between $1 and a few thousand
+			price := int64(100 + r.ExpFloat64()*4000)
+			if r.IntN(10) == 0 {
+				price *= 10
+			}
+			var compareAt any
+			if r.IntN(4) == 0 {
+				compareAt = cents(price + price*int64(10+r.IntN(40))/100)
+			}
+			cost := price * int64(40+r.IntN(40)) / 100
+
+			status := weighted(r, []string{"active", "inactive", "out_of_stock", "discontinued"}, []int{85, 5, 7, 3})
+			stock := r.IntN(500)
+			if status == "out_of_stock" {
+				stock = 0
+			}
+
+			createdAt := randomTime(r, 800)
+			updatedAt := createdAt.Add(time.Duration(r.IntN(90*24)) * time.Hour)
+
+			g.products = append(g.products, genProduct{ID: id, SKU: sku, Name: name, Price: price})
+
+			if err := emit(
+				id, sku, name, fmt.Sprintf("%s-p%d", g.prefix, i+1),
+				"Generated product "+name+". "+pick(r, blurbs), pick(r, blurbs),
+				g.categoryBase+int64(r.IntN(g.profile.Categories))+1, status,
+				cents(price), compareAt, cents(cost), stock, 5+r.IntN(20),
+				fmt.Sprintf("%.2f", 0.1+r.Float64()*20),
+				fmt.Sprintf("%dx%dx%d", 5+r.IntN(60), 5+r.IntN(60), 1+r.IntN(40)),
+				ean13(r), brand+" Manufacturing", brand, r.IntN(50_000), r.IntN(50) == 0,
+				name, "Buy "+name+" online", createdAt, updatedAt,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// addressesBefore returns how many addresses customers [0, i) have:
+// every customer has one, every fourth customer has a second
+func addressesBefore(i int) int64 {
+	return int64(i + (i+3)/4)
+}
+
+func (g *generator) copyCustomers(tx *sql.Tx) (int, error) {
+	columns := []string{"id", "email", "first_name", "last_name", "phone", "date_of_birth", "is_active",
+		"email_verified_at", "last_login_at", "created_at", "updated_at"}
+
+	return copyRows(tx, "customers", columns, func(emit func(...any) error) error {
+		for i := 0; i < g.profile.Customers; i++ {
+			r := g.rng(streamCustomers, i)
+			first, last := pick(r, firstNames), pick(r, lastNames)
+			createdAt := randomTime(r, 1000)
+			birth := time.Date(1950+r.IntN(55), time.Month(1+r.IntN(12)), 1+r.IntN(28), 0, 0, 0, 0, time.UTC)
+
+			var verifiedAt, lastLogin any
+			if r.IntN(10) < 8 {
+				verifiedAt = createdAt.Add(time.Duration(r.IntN(48)) * time.Hour)
+				lastLogin = createdAt.Add(time.Duration(r.IntN(600*24)) * time.Hour)
+			}
+
+			if err := emit(
+				g.customerBase+int64(i)+1,
+				fmt.Sprintf("%s.%s.%d@%s.example.com", strings.ToLower(first), strings.ToLower(last), i+1, g.prefix),
+				first, last, fmt.Sprintf("+1-555-%04d", r.IntN(10000)), birth,
+				r.IntN(50) != 0, verifiedAt, lastLogin, createdAt, createdAt,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (g *generator) copyAddresses(tx *sql.Tx) (int, error) {
+	columns := []string{"id", "customer_id", "address_type", "first_name", "last_name", "address_line1",
+		"city", "state", "postal_code", "country", "phone", "is_default", "created_at", "updated_at"}
+
+	return copyRows(tx, "customer_addresses", columns, func(emit func(...any) error) error {
+		for i := 0; i < g.profile.Customers; i++ {
+			// Same stream as the customer row so names match
+			r := g.rng(streamCustomers, i)
+			first, last := pick(r, firstNames), pick(r, lastNames)
+			createdAt := randomTime(r, 1000)
+
+			count := 1
+			if i%4 == 0 {
+				count = 2
+			}
+			for a := 0; a < count; a++ {
+				addressType, isDefault := "both", true
+				if a == 1 {
+					addressType, isDefault = "shipping", false
+				}
+				city := pick(r, cities)
+				if err := emit(
+					g.addressBase+addressesBefore(i)+int64(a)+1, g.customerBase+int64(i)+1,
+					addressType, first, last,
+					fmt.Sprintf("%d %s %s", 1+r.IntN(9999), pick(r, lastNames), pick(r, streetSuffixes)),
+					city[0], city[1], fmt.Sprintf("%05d", r.IntN(100000)), "United States",
+					fmt.Sprintf("+1-555-%04d", r.IntN(10000)), isDefault, createdAt, createdAt,
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// order derives order i, its items and totals from the order stream
+func (g *generator) order(i int) genOrder {
+	r := g.rng(streamOrders, i)
+
+	customer := r.IntN(g.profile.Customers)
+	addressID := g.addressBase + addressesBefore(customer) + 1
+	if customer%4 == 0 && r.IntN(2) == 0 {
+		addressID++
+	}
+
+	o := genOrder{
+		ID:         g.orderBase + int64(i) + 1,
+		Number:     fmt.Sprintf("%s-%08d", strings.ToUpper(g.prefix), i+1),
+		CustomerID: g.customerBase + int64(customer) + 1,
+		AddressID:  addressID,
+		Status: weighted(r,
+			[]string{"pending", "confirmed", "processing", "shipped", "delivered", "cancelled", "refunded"},
+			[]int{5, 5, 5, 10, 65, 7, 3}),
+		CreatedAt: randomTime(r, 730),
+	}
+	o.UpdatedAt = o.CreatedAt.Add(time.Duration(r.IntN(14*24)) * time.Hour)
+
+	seen := make(map[int]bool)
+	for n := 1 + r.IntN(5); len(o.Items) < n; {
+		idx := r.IntN(len(g.products))
+		if seen[idx] {
+			continue
+		}
+		seen[idx] = true
+
+		p := g.products[idx]
+		item := genOrderItem{ProductID: p.ID, SKU: p.SKU, Name: p.Name, Quantity: 1 + r.IntN(3), UnitPrice: p.Price}
+		if r.IntN(10) == 0 {
+			item.Discount = item.UnitPrice * int64(item.Quantity) / 10
+		}
+		o.Items = append(o.Items, item)
+		o.Subtotal += item.UnitPrice*int64(item.Quantity) - item.Discount
+	}
+
+	o.Tax = o.Subtotal * 8 / 100
+	if o.Subtotal < 5000 {
+		o.Shipping = 599
+	}
+	if r.IntN(8) == 0 {
+		o.Discount = o.Subtotal / 20
+	}
+
+	if o.Status == "cancelled" {
+		cancelledAt := o.UpdatedAt
+		reason := pick(r, cancelReasons)
+		o.CancelledAt, o.CancelledReason = &cancelledAt, &reason
+	}
+
+	return o
+}
+
+func (o genOrder) total() int64 {
+	return o.Subtotal + o.Tax + o.Shipping - o.Discount
+}
+
+func (g *generator) copyOrders(tx *sql.Tx) (int, error) {
+	columns := []string{"id", "order_number", "customer_id", "status", "subtotal", "tax_amount", "shipping_amount",
+		"discount_amount", "total_amount", "currency", "shipping_address_id", "billing_address_id",
+		"cancelled_at", "cancelled_reason", "created_at", "updated_at"}
+
+	return copyRows(tx, "orders", columns, func(emit func(...any) error) error {
+		for i := 0; i < g.profile.Orders; i++ {
+			o := g.order(i)
+			if err := emit(
+				o.ID, o.Number, o.CustomerID, o.Status, cents(o.Subtotal), cents(o.Tax), cents(o.Shipping),
+				cents(o.Discount), cents(o.total()), "USD", o.AddressID, o.AddressID,
+				nullable(o.CancelledAt), nullable(o.CancelledReason), o.CreatedAt, o.UpdatedAt,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (g *generator) copyOrderItems(tx *sql.Tx) (int, error) {
+	columns := []string{"order_id", "product_id", "sku", "name", "quantity", "unit_price", "discount_amount",
+		"total_price", "created_at"}
+
+	return copyRows(tx, "order_items", columns, func(emit func(...any) error) error {
+		for i := 0; i < g.profile.Orders; i++ {
+			o := g.order(i)
+			for _, item := range o.Items {
+				if err := emit(
+					o.ID, item.ProductID, item.SKU, item.Name, item.Quantity, cents(item.UnitPrice),
+					cents(item.Discount), cents(item.UnitPrice*int64(item.Quantity)-item.Discount), o.CreatedAt,
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (g *generator) copyPayments(tx *sql.Tx) (int, error) {
+	columns := []string{"order_id", "payment_method", "status", "amount", "currency", "transaction_id",
+		"gateway_response", "paid_at", "created_at", "updated_at"}
+
+	methods := []string{"credit_card", "debit_card", "paypal", "bank_transfer", "cash_on_delivery"}
+
+	return copyRows(tx, "payments", columns, func(emit func(...any) error) error {
+		for i := 0; i < g.profile.Orders; i++ {
+			o := g.order(i)
+			// A separate stream keeps payment choices from shifting order contents
+			r := rand.New(rand.NewPCG(g.seed^0x9e3779b97f4a7c15, uint64(i)))
+
+			var statuses []string
+			switch o.Status {
+			case "pending":
+				statuses = []string{"pending"}
+			case "cancelled":
+				statuses = []string{"failed"}
+			case "refunded":
+				statuses = []string{"completed", "refunded"}
+			default:
+				statuses = []string{"completed"}
+			}
+			// A share of orders had a failed attempt before succeeding
+			if statuses[0] == "completed" && r.IntN(20) == 0 {
+				statuses = append([]string{"failed"}, statuses...)
+			}
+
+			for n, status := range statuses {
+				var paidAt any
+				if status == "completed" || status == "refunded" {
+					paidAt = o.CreatedAt.Add(time.Duration(n+1) * time.Minute)
+				}
+				if err := emit(
+					o.ID, pick(r, methods), status, cents(o.total()), "USD",
+					fmt.Sprintf("txn_%s_%d_%d", g.prefix, i+1, n+1),
+					fmt.Sprintf(`{"gateway":"simulated","attempt":%d}`, n+1),
+					paidAt, o.CreatedAt, o.UpdatedAt,
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (g *generator) copyReviews(tx *sql.Tx) (int, error) {
+	columns := []string{"product_id", "customer_id", "order_id", "rating", "title", "review_text", "is_approved",
+		"helpful_count", "created_at", "updated_at"}
+
+	// One review per (product, customer), enforced by a unique constraint
+	reviewed := make(map[[2]int64]bool)
+
+	return copyRows(tx, "product_reviews", columns, func(emit func(...any) error) error {
+		for i := 0; i < g.profile.Orders; i++ {
+			o := g.order(i)
+			if o.Status != "delivered" {
+				continue
+			}
+			r := rand.New(rand.NewPCG(g.seed^0xc2b2ae3d27d4eb4f, uint64(i)))
+
+			for _, item := range o.Items {
+				key := [2]int64{item.ProductID, o.CustomerID}
+				if reviewed[key] || r.Float64() >= g.profile.ReviewRate {
+					continue
+				}
+				reviewed[key] = true
+
+				rating := weightedInt(r, []int{1, 2, 3, 4, 5}, []int{5, 5, 15, 35, 40})
+				createdAt := o.UpdatedAt.Add(time.Duration(1+r.IntN(30*24)) * time.Hour)
+				if err := emit(
+					item.ProductID, o.CustomerID, o.ID, rating, reviewTitles[rating-1], pick(r, blurbs),
+					r.IntN(10) != 0, r.IntN(100), createdAt, createdAt,
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// ===========================
+// Value helpers
+// ===========================
+
+func cents(v int64) string {
+	return fmt.Sprintf("%d.%02d", v/100, v%100)
+}
+
+func nullable[T any](v *T) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func pick[T any](r *rand.Rand, values []T) T {
+	return values[r.IntN(len(values))]
+}
+
+func weighted(r *rand.Rand, values []string, weights []int) string {
+	return values[weightedIndex(r, weights)]
+}
+
+func weightedInt(r *rand.Rand, values []int, weights []int) int {
+	return values[weightedIndex(r, weights)]
+}
+
+func weightedIndex(r *rand.Rand, weights []int) int {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	n := r.IntN(total)
+	for i, w := range weights {
+		if n < w {
+			return i
+		}
+		n -= w
+	}
+	return len(weights) - 1
+}
+
+// randomTime returns a time up to maxDaysAgo days before generationEpoch
+func randomTime(r *rand.Rand, maxDaysAgo int) time.Time {
+	return generationEpoch.Add(-time.Duration(r.IntN(maxDaysAgo*24*3600)) * time.Second)
+}
+
+// ean13 returns a random EAN-13 barcode with a valid check digit
+func ean13(r *rand.Rand) string {
+	digits := make([]byte, 13)
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := r.IntN(10)
+		digits[i] = byte('0' + d)
+		if i%2 == 0 {
+			sum += d
+		} else {
+			sum += 3 * d
+		}
+	}
+	digits[12] = byte('0' + (10-sum%10)%10)
+	return string(digits)
+}
+
+// ===========================
+// Word lists
+// ===========================
+
+var (
+	firstNames = []string{"James", "Mary", "Robert", "Patricia", "John", "Jennifer", "Michael", "Linda", "David", "Elizabeth",
+		"William", "Barbara", "Richard", "Susan", "Joseph", "Jessica", "Thomas", "Sarah", "Charles", "Karen",
+		"Wei", "Aisha", "Mateo", "Priya", "Kenji", "Fatima", "Lucas", "Amara", "Noah", "Sofia"}
+	lastNames = []string{"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez",
+		"Hernandez", "Lopez", "Gonzalez", "Wilson", "Anderson", "Thomas", "Taylor", "Moore", "Jackson", "Martin",
+		"Chen", "Khan", "Silva", "Patel", "Tanaka", "Okafor", "Novak", "Rossi", "Kim", "Nguyen"}
+	cities = [][2]string{{"New York", "NY"}, {"Los Angeles", "CA"}, {"Chicago", "IL"}, {"Houston", "TX"}, {"Phoenix", "AZ"},
+		{"Philadelphia", "PA"}, {"San Antonio", "TX"}, {"San Diego", "CA"}, {"Dallas", "TX"}, {"Seattle", "WA"},
+		{"Denver", "CO"}, {"Boston", "MA"}, {"Portland", "OR"}, {"Atlanta", "GA"}, {"Miami", "FL"}}
+	streetSuffixes = []string{"St", "Ave", "Blvd", "Rd", "Ln", "Dr", "Way", "Ct"}
+	categoryNouns  = []string{"Electronics", "Laptops", "Phones", "Audio", "Cameras", "Books", "Clothing", "Shoes", "Kitchen",
+		"Garden", "Toys", "Sports", "Outdoors", "Beauty", "Health", "Office", "Furniture", "Lighting", "Pets", "Tools"}
+	adjectives = []string{"Premium", "Classic", "Ultra", "Compact", "Smart", "Eco", "Pro", "Essential", "Deluxe", "Portable",
+		"Wireless", "Vintage", "Modern", "Rugged", "Lightweight"}
+	productNouns = []string{"Headphones", "Backpack", "Kettle", "Lamp", "Keyboard", "Mouse", "Jacket", "Sneakers", "Blender",
+		"Speaker", "Monitor", "Chair", "Notebook", "Watch", "Camera", "Tent", "Bottle", "Charger", "Desk", "Drone"}
+	brands = []string{"Acme", "Globex", "Initech", "Umbrella", "Stark", "Wayne", "Hooli", "Vandelay", "Soylent", "Tyrell",
+		"Wonka", "Cyberdyne", "Aperture", "Gringotts", "Oscorp"}
+	blurbs = []string{"Built to last with quality materials.", "A customer favourite for everyday use.",
+		"Designed for comfort and performance.", "Great value for the price.", "Sleek design that fits anywhere.",
+		"Tested for durability in tough conditions.", "Easy to set up and simple to use."}
+	reviewTitles  = []string{"Disappointed", "Not great", "It's okay", "Very good", "Excellent!"}
+	cancelReasons = []string{"Customer changed mind", "Found a better price", "Payment failed", "Shipping too slow", "Ordered by mistake"}
+)
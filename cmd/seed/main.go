@@ -1,14 +1,9 @@
 package main
 
 import (
-	"database/sql"
 	"flag"
 	"fmt"
 	"log"
-	"os"
-	"path/filepath"
-	"sort"
-	"strings"
 
 	"ecom/internal/config"
 	"ecom/internal/database"
@@ -16,7 +11,13 @@ import (
 
 func main() {
 	var (
-		clear = flag.Bool("clear", false, "Clear all data before seeding")
+		clear    = flag.Bool("clear", false, "Clear all data (TRUNCATE ... RESTART IDENTITY) before seeding")
+		confirm  = flag.Bool("confirm-production", false, "Allow -clear when ENV=production")
+		dir      = flag.String("dir", "seeds", "Seeds directory; files in <dir>/<env>/ run only in that environment")
+		env      = flag.String("env", "", "Environment whose seeds to run; must match ENV when set")
+		generate = flag.Bool("generate", false, "Generate a synthetic dataset instead of running the seed files")
+		profile  = flag.String("profile", "small", "Dataset size for -generate: small, medium or large")
+		seed     = flag.Uint64("seed", 42, "Random seed for -generate; the same seed produces the same dataset")
 	)
 	flag.Parse()
 
@@ -26,99 +27,48 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// -env only confirms ENV: seeding one environment's files into another's
+	// database, or dodging the production guard with it, is refused
+	environment := cfg.Env
+	if *env != "" && *env != cfg.Env {
+		log.Fatalf("-env=%s does not match ENV=%s (database %q on %s)", *env, cfg.Env, cfg.Database.Name, cfg.Database.Host)
+	}
+
+	if *clear && (cfg.Env == "production" || *env == "production") && !*confirm {
+		log.Fatalf("Refusing to clear data in production (database %q on %s); pass -confirm-production to proceed",
+			cfg.Database.Name, cfg.Database.Host)
+	}
+
 	// Connect to database
-	if err := database.Connect(cfg); err != nil {
+	db, err := database.Connect(cfg)
+	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer database.Close()
-
-	db := database.GetDB()
+	defer db.Close()
+	runner := newSeedRunner(db, *dir, environment)
 
 	if *clear {
-		fmt.Println("🗑️  Clearing existing data...")
-		if err := clearData(db); err != nil {
+		fmt.Printf("🗑️  Clearing existing data in %q...\n", cfg.Database.Name)
+		if err := runner.clear(); err != nil {
 			log.Fatalf("Failed to clear data: %v", err)
 		}
 		fmt.Println("✅ Data cleared")
 	}
 
-	fmt.Println("🌱 Seeding database with mock data...")
-
-	// Run all seed files
-	if err := runSeedFiles(db); err != nil {
-		log.Fatalf("Failed to seed database: %v", err)
-	}
-
-	fmt.Println("✅ Database seeding completed successfully!")
-}
-
-func clearData(db *sql.DB) error {
-	tables := []string{
-		"order_items",
-		"order_coupons",
-		"payments",
-		"orders",
-		"wishlist_items",
-		"wishlists",
-		"product_reviews",
-		"shipping_details",
-		"inventory_movements",
-		"product_images",
-		"customer_addresses",
-		"products",
-		"customers",
-		"coupons",
-		"categories",
-	}
-
-	for _, table := range tables {
-		if _, err := db.Exec(fmt.Sprintf("DELETE FROM %s CASCADE", table)); err != nil {
-			// Continue even if some tables don't exist
-			continue
+	if *generate {
+		fmt.Printf("🎲 Generating %s dataset (seed %d)...\n", *profile, *seed)
+		if err := generateData(db, *profile, *seed); err != nil {
+			log.Fatalf("Failed to generate data: %v", err)
 		}
+		fmt.Println("✅ Data generation completed successfully!")
+		return
 	}
 
-	return nil
-}
+	fmt.Printf("🌱 Seeding database for %s...\n", environment)
 
-func runSeedFiles(db *sql.DB) error {
-	// Get seed files from seeds directory
-	seedDir := "seeds"
-	files, err := os.ReadDir(seedDir)
-	if err != nil {
-		return fmt.Errorf("failed to read seeds directory: %w", err)
-	}
-
-	// Filter and sort SQL files
-	var sqlFiles []string
-	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".sql") {
-			sqlFiles = append(sqlFiles, file.Name())
-		}
-	}
-	sort.Strings(sqlFiles)
-
-	if len(sqlFiles) == 0 {
-		fmt.Println("  ⚠️  No seed files found in seeds directory")
-		return nil
-	}
-
-	// Execute each seed file
-	for _, filename := range sqlFiles {
-		filepath := filepath.Join(seedDir, filename)
-		fmt.Printf("  📄 Running %s...\n", filename)
-
-		content, err := os.ReadFile(filepath)
-		if err != nil {
-			return fmt.Errorf("failed to read seed file %s: %w", filename, err)
-		}
-
-		if _, err := db.Exec(string(content)); err != nil {
-			return fmt.Errorf("failed to execute seed file %s: %w", filename, err)
-		}
-
-		fmt.Printf("    ✓ %s completed\n", filename)
+	if err := runner.run(); err != nil {
+		log.Fatalf("Failed to seed database: %v", err)
 	}
 
-	return nil
+	fmt.Println("✅ Database seeding completed successfully!")
 }
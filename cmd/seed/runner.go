@@ -0,0 +1,224 @@
+package main
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// seedTables lists every table holding seedable data, children before parents,
+// so the order matches the foreign key dependencies
+var seedTables = []string{
+	"order_items",
+	"order_coupons",
+	"payments",
+	"shipping_details",
+	"product_reviews",
+	"orders",
+	"wishlist_items",
+	"wishlists",
+	"inventory_movements",
+	"product_images",
+	"customer_addresses",
+	"products",
+	"customers",
+	"coupons",
+	"categories",
+}
+
+// seedFile is a seed SQL file relative to the seeds directory
+type seedFile struct {
+	Name string // e.g. "development/001_seed_categories.sql"
+	Path string
+}
+
+// seedRun is a seed file recorded in seed_runs
+type seedRun struct {
+	Checksum    string
+	Environment string
+	RunAt       time.Time
+}
+
+// seedRunner executes seed files once per database and records them
+type seedRunner struct {
+	db  *sql.DB
+	dir string
+	env string
+}
+
+func newSeedRunner(db *sql.DB, dir, env string) *seedRunner {
+	return &seedRunner{db: db, dir: dir, env: env}
+}
+
+// init creates the seed_runs tracking table
+func (s *seedRunner) init() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS seed_runs (
+			filename VARCHAR(255) PRIMARY KEY,
+			checksum VARCHAR(64) NOT NULL,
+			environment VARCHAR(50) NOT NULL,
+			execution_time_ms BIGINT NOT NULL DEFAULT 0,
+			run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`
+	if _, err := s.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create seed_runs table: %w", err)
+	}
+	return nil
+}
+
+// files returns the shared seed files (directly in the seeds directory)
+// followed by the files of the current environment's subdirectory
+func (s *seedRunner) files() ([]seedFile, error) {
+	var files []seedFile
+
+	for _, sub := range []string{"", s.env} {
+		dir := filepath.Join(s.dir, sub)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if sub != "" && os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read seeds directory %s: %w", dir, err)
+		}
+
+		var names []string
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
+				names = append(names, entry.Name())
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			files = append(files, seedFile{
+				Name: filepath.ToSlash(filepath.Join(sub, name)),
+				Path: filepath.Join(dir, name),
+			})
+		}
+	}
+
+	return files, nil
+}
+
+func (s *seedRunner) applied() (map[string]seedRun, error) {
+	rows, err := s.db.Query("SELECT filename, checksum, environment, run_at FROM seed_runs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query seed runs: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]seedRun)
+	for rows.Next() {
+		var name string
+		var run seedRun
+		if err := rows.Scan(&name, &run.Checksum, &run.Environment, &run.RunAt); err != nil {
+			return nil, fmt.Errorf("failed to scan seed run: %w", err)
+		}
+		applied[name] = run
+	}
+	return applied, rows.Err()
+}
+
+// run executes every seed file that has not been recorded yet. A recorded file
+// whose content changed is an error: add a new seed file instead, or -clear.
+func (s *seedRunner) run() error {
+	if err := s.init(); err != nil {
+		return err
+	}
+
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Printf("  ⚠️  No seed files found for environment %q\n", s.env)
+		return nil
+	}
+
+	applied, err := s.applied()
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, file := range files {
+		content, err := os.ReadFile(file.Path)
+		if err != nil {
+			return fmt.Errorf("failed to read seed file %s: %w", file.Name, err)
+		}
+		sum := sha256.Sum256(content)
+		checksum := hex.EncodeToString(sum[:])
+
+		if run, ok := applied[file.Name]; ok {
+			if run.Checksum != checksum {
+				return fmt.Errorf("seed file %s was modified after it ran on %s; add a new seed file or run with -clear",
+					file.Name, run.RunAt.Format("2006-01-02 15:04:05"))
+			}
+			fmt.Printf("  ⏭️  %s already applied\n", file.Name)
+			continue
+		}
+
+		fmt.Printf("  📄 Running %s...\n", file.Name)
+		if err := s.execute(file, string(content), checksum); err != nil {
+			return err
+		}
+		count++
+	}
+
+	if count == 0 {
+		fmt.Println("  ✅ No new seed files")
+	}
+	return nil
+}
+
+// execute runs one seed file and records it in the same transaction
+func (s *seedRunner) execute(file seedFile, content, checksum string) error {
+	start := time.Now()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(content); err != nil {
+		return fmt.Errorf("failed to execute seed file %s: %w", file.Name, err)
+	}
+
+	elapsed := time.Since(start)
+	if _, err := tx.Exec(
+		"INSERT INTO seed_runs (filename, checksum, environment, execution_time_ms) VALUES ($1, $2, $3, $4)",
+		file.Name, checksum, s.env, elapsed.Milliseconds(),
+	); err != nil {
+		return fmt.Errorf("failed to record seed file %s: %w", file.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit seed file %s: %w", file.Name, err)
+	}
+
+	fmt.Printf("    ✓ %s completed (%v)\n", file.Name, elapsed.Round(time.Millisecond))
+	return nil
+}
+
+// clear empties every seeded table and the seed_runs history in one
+// statement, resetting the ID sequences
+func (s *seedRunner) clear() error {
+	if err := s.init(); err != nil {
+		return err
+	}
+
+	tables := append(append([]string{}, seedTables...), "seed_runs")
+	query := fmt.Sprintf("TRUNCATE TABLE %s RESTART IDENTITY CASCADE", strings.Join(tables, ", "))
+	if _, err := s.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to truncate tables: %w", err)
+	}
+	return nil
+}
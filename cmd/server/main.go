@@ -2,17 +2,21 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
+	"ecom/internal/auth"
 	"ecom/internal/config"
 	"ecom/internal/database"
-	"ecom/internal/routes"
+	"ecom/internal/logging"
+	"ecom/internal/tracing"
+	"ecom/migrations"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,25 +25,86 @@ import (
 // @version 1.0
 // @description E-commerce REST API with full CRUD operations
 // @host localhost:8080
-// @basePath /api/v1
+// @basePath /
 // @schemes http https
 // @consumes application/json
 // @produces application/json
 // @license.name Apache 2.0
 // @license.url http://www.apache.org/licenses/LICENSE-2.0.html
+// @tag.name Health
+// @tag.description System health endpoints
+// @tag.name Categories
+// @tag.description Category management
+// @tag.name Products
+// @tag.description Product management
+// @tag.name Auth
+// @tag.description Customer and staff authentication
+// @securityDefinitions.bearer BearerAuth JWT
 
 func main() {
+	var (
+		configFile  = flag.String("config", "", "YAML or TOML config file; environment variables override it (default: CONFIG_FILE)")
+		printConfig = flag.Bool("print-config", false, "Print the effective configuration, secrets redacted, and exit")
+	)
+	flag.Parse()
+
 	// Load configuration
-	cfg, err := config.LoadConfig()
+	cfg, err := config.Load(*configFile)
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	if *printConfig {
+		if err := cfg.Redacted().WriteYAML(os.Stdout); err != nil {
+			log.Fatalf("Failed to print config: %v", err)
+		}
+		return
+	}
+
+	// Structured logging; the log package (startup and migration messages)
+	// goes through the same handler
+	logger, err := logging.New(os.Stdout, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		log.Fatalf("Invalid logging config: %v", err)
+	}
+	slog.SetDefault(logger)
+
+	// Tracing (a no-op unless TRACING_EXPORTER is set)
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, cfg.Env)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+
+	// Access token keys; without configured keys tokens do not survive a
+	// restart, or verify on other replicas
+	if len(cfg.Auth.SigningKeys) == 0 {
+		if cfg.Env == "production" {
+			log.Fatal("AUTH_SIGNING_KEYS must be set in production")
+		}
+		logger.Warn("AUTH_SIGNING_KEYS is not set; signing access tokens with an ephemeral key")
+	}
+	tokens, err := auth.NewTokens(cfg.Auth)
+	if err != nil {
+		log.Fatalf("Failed to set up authentication: %v", err)
+	}
+
 	// Connect to database
-	if err := database.Connect(cfg); err != nil {
+	db, err := database.Connect(cfg)
+	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer database.Close()
+	defer db.Close()
+
+	// Migrations are embedded in the binary unless MIGRATIONS_DIR is set
+	migrationRunner := migrations.NewRunner(db, cfg.Migrations.Dir)
+
+	// Optionally apply pending migrations before serving (under an advisory lock)
+	if cfg.Migrations.OnStart {
+		log.Println("🔄 MIGRATE_ON_START enabled, applying pending migrations...")
+		if err := migrationRunner.Up(); err != nil {
+			log.Fatalf("Failed to apply migrations: %v", err)
+		}
+	}
 
 	// Set Gin mode
 	if cfg.Server.Environment == "production" {
@@ -48,26 +113,29 @@ func main() {
 		gin.SetMode(gin.DebugMode)
 	}
 
-	// Create Gin router
-	router := gin.Default()
-
-	// Setup routes
-	routes.SetupRoutes(router)
+	// Wire repositories, services and handlers into the router
+	router := newRouter(cfg, logger, db, migrationRunner, tokens)
 
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port),
 		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
 	// Start server in a goroutine
 	go func() {
 		log.Printf("🚀 Server starting on %s:%s", cfg.Server.Host, cfg.Server.Port)
 		log.Printf("📝 Health check: http://%s:%s/health", cfg.Server.Host, cfg.Server.Port)
-		log.Printf("📚 Swagger docs: http://%s:%s/swagger/index.html", cfg.Server.Host, cfg.Server.Port)
+		log.Printf("📝 Readiness: http://%s:%s/health/ready", cfg.Server.Host, cfg.Server.Port)
+		if cfg.Metrics.Enabled {
+			log.Printf("📈 Metrics: http://%s:%s%s", cfg.Server.Host, cfg.Server.Port, cfg.Metrics.Path)
+		}
+		if cfg.Docs.Enabled {
+			log.Printf("📚 API docs: http://%s:%s/swagger/", cfg.Server.Host, cfg.Server.Port)
+		}
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
@@ -81,13 +149,18 @@ func main() {
 	log.Println("🛑 Shutting down server...")
 
 	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// Flush the spans of the last requests
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("⚠️  Failed to flush traces: %v", err)
+	}
+
 	log.Println("✅ Server exited")
 }
 
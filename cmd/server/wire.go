@@ -0,0 +1,121 @@
+package main
+
+import (
+	"database/sql"
+	"log/slog"
+
+	"ecom/docs"
+	"ecom/internal/auth"
+	"ecom/internal/config"
+	"ecom/internal/database"
+	"ecom/internal/handlers"
+	"ecom/internal/metrics"
+	"ecom/internal/middleware"
+	"ecom/internal/openapi"
+	"ecom/internal/repositories"
+	"ecom/internal/routes"
+	"ecom/internal/services"
+	"ecom/internal/tracing"
+	"ecom/pkg/migrate"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newRouter is the composition root: it wires the database into repositories,
+// repositories into services, services into handlers and handlers into routes.
+// Nothing below this point reaches for globals.
+func newRouter(cfg *config.Config, logger *slog.Logger, db *sql.DB, migrationRunner *migrate.MigrationRunner, tokens *auth.Tokens) *gin.Engine {
+	// Metrics; the business counters are recorded by the services
+	var m *metrics.Metrics
+	if cfg.Metrics.Enabled {
+		m = metrics.New()
+		m.RegisterDB(db, cfg.Database.Name)
+		m.RegisterMigrations(migrationRunner)
+	}
+	var business *metrics.Business
+	if m != nil {
+		business = m.Business
+	}
+
+	// Unit of work shared by the services
+	txManager := database.NewTxManager(db, nil)
+
+	// Repositories
+	categoryRepo := repositories.NewCategoryRepository(db, cfg.Database.QueryTimeout)
+	productRepo := repositories.NewProductRepository(db, cfg.Database.QueryTimeout)
+	customerRepo := repositories.NewCustomerRepository(db, cfg.Database.QueryTimeout)
+	staffRepo := repositories.NewStaffUserRepository(db, cfg.Database.QueryTimeout)
+	refreshTokenRepo := repositories.NewRefreshTokenRepository(db, cfg.Database.QueryTimeout)
+	roleRepo := repositories.NewRoleRepository(db, cfg.Database.QueryTimeout)
+
+	// Services
+	categoryService := services.NewCategoryService(txManager, categoryRepo, productRepo)
+	productService := services.NewProductService(txManager, productRepo, business)
+	authService := services.NewAuthService(txManager, customerRepo, staffRepo, refreshTokenRepo, roleRepo, tokens)
+
+	// Handlers
+	h := &routes.Handlers{
+		Health:   handlers.NewHealthHandler(db, migrationRunner),
+		Category: handlers.NewCategoryHandler(categoryService),
+		Product:  handlers.NewProductHandler(productService),
+		Auth:     handlers.NewAuthHandler(authService),
+	}
+
+	opts := routes.Options{
+		Logger:         logger,
+		RequestTimeout: cfg.Server.RequestTimeout,
+		Tracing:        cfg.Tracing.Exporter != tracing.ExporterNone && cfg.Tracing.Exporter != "",
+		Metrics:        m,
+		MetricsPath:    cfg.Metrics.Path,
+		CORS:           corsPolicy(cfg.CORS),
+		AdminCORS:      corsPolicy(cfg.AdminCORS),
+		Tokens:         tokens,
+	}
+	if cfg.Docs.Enabled {
+		opts.Docs = &routes.DocsOptions{
+			Title:    cfg.Docs.Title,
+			SpecPath: cfg.Docs.SpecPath,
+			ReDoc:    docs.ReDoc(),
+		}
+		if cfg.Docs.EmbeddedSpec {
+			opts.Docs.Spec = docs.Spec
+		}
+	}
+	if cfg.Server.ValidateOpenAPI && cfg.Env != "production" {
+		validator, err := loadSpec(cfg)
+		if err != nil {
+			logger.Warn("OpenAPI validation disabled", slog.Any("error", err))
+		} else {
+			opts.OpenAPIValidator = validator
+		}
+	}
+
+	// Not gin.Default(): requests are logged by middleware.RequestLogger and
+	// panics recovered by middleware.ErrorHandler
+	router := gin.New()
+	routes.SetupRoutes(router, h, opts)
+	return router
+}
+
+// corsPolicy hands a configured CORS policy to the middleware. It never
+// returns nil: an unset policy is the default one, which allows no origins,
+// so staff operations are same-origin only until CORS_ADMIN_ALLOWED_ORIGINS
+// is set, whatever CORS_ALLOWED_ORIGINS says.
+func corsPolicy(cfg config.CORSConfig) *middleware.CORSPolicy {
+	return &middleware.CORSPolicy{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   cfg.AllowedMethods,
+		AllowedHeaders:   cfg.AllowedHeaders,
+		ExposedHeaders:   cfg.ExposedHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	}
+}
+
+// loadSpec loads the same spec the server serves
+func loadSpec(cfg *config.Config) (*openapi.Validator, error) {
+	if cfg.Docs.EmbeddedSpec {
+		return openapi.Parse(docs.Spec)
+	}
+	return openapi.Load("docs/swagger.yaml")
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ecom/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestUnsetAdminCORSAllowsNoOrigins(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://shop.example.com")
+	t.Setenv("CORS_ADMIN_ALLOWED_ORIGINS", "")
+	t.Setenv("METRICS_ENABLED", "false")
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if policy := corsPolicy(cfg.AdminCORS); policy == nil || len(policy.AllowedOrigins) != 0 {
+		t.Fatalf("unset admin policy = %+v, want one allowing no origins", policy)
+	}
+
+	// Preflights are answered before handlers, so no database is needed
+	gin.SetMode(gin.TestMode)
+	router := newRouter(cfg, slog.New(slog.DiscardHandler), nil, nil, nil)
+	tests := []struct {
+		method string
+		status int
+	}{
+		{http.MethodGet, http.StatusNoContent},
+		// Staff operations fall back to the default admin policy, not CORS
+		{http.MethodPost, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodOptions, "/api/v1/products", nil)
+		req.Header.Set("Origin", "https://shop.example.com")
+		req.Header.Set("Access-Control-Request-Method", tt.method)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s preflight: status %d, want %d", tt.method, rec.Code, tt.status)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"ecom/internal/auth"
+	"ecom/internal/config"
+	"ecom/internal/database"
+	"ecom/internal/repositories"
+	"ecom/internal/services"
+)
+
+// Staff accounts are not created through the API; this command creates them
+func main() {
+	var (
+		email = flag.String("email", "", "Email the staff user logs in with")
+		name  = flag.String("name", "", "Display name")
+		roles = flag.String("role", "", "Comma-separated roles: admin, catalog_manager, warehouse or support")
+	)
+	flag.Parse()
+
+	if *email == "" || *name == "" || *roles == "" {
+		log.Fatal("-email, -name and -role are required")
+	}
+
+	// The password is read from STAFF_PASSWORD or the first line of stdin,
+	// never from a flag, so it stays out of the shell history and ps
+	password := os.Getenv("STAFF_PASSWORD")
+	if password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			log.Fatalf("Failed to read password: %v", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+	if len(password) < auth.MinPasswordLength {
+		log.Fatalf("The password must be at least %d characters", auth.MinPasswordLength)
+	}
+
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Connect to database
+	db, err := database.Connect(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Only the staff and role repositories are used; the service hashes
+	// the password
+	authService := services.NewAuthService(
+		database.NewTxManager(db, nil),
+		nil,
+		repositories.NewStaffUserRepository(db, cfg.Database.QueryTimeout),
+		nil,
+		repositories.NewRoleRepository(db, cfg.Database.QueryTimeout),
+		nil,
+	)
+	id, err := authService.CreateStaffUser(context.Background(), *email, *name, password, strings.Split(*roles, ","))
+	if err != nil {
+		log.Fatalf("Failed to create staff user: %v", err)
+	}
+
+	fmt.Printf("✅ Created staff user %d (%s) with roles %s\n", id, strings.ToLower(*email), *roles)
+}
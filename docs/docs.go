@@ -0,0 +1,29 @@
+// Package docs embeds the OpenAPI spec generated by cmd/openapi, so the
+// server can serve it without docs/ being deployed next to the binary.
+package docs
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// Spec is docs/swagger.yaml as of the build. Regenerate it after changing
+// routes, handler annotations or DTOs:
+//
+//	go run ./cmd/openapi
+//
+//go:embed swagger.yaml
+var Spec []byte
+
+// Vendor the ReDoc bundle; see redoc/README.md
+//
+//go:generate curl -fsSL -o redoc/redoc.standalone.js https://cdn.jsdelivr.net/npm/redoc@2.1.5/bundles/redoc.standalone.js
+
+//go:embed redoc
+var redoc embed.FS
+
+// ReDoc holds the vendored ReDoc assets (redoc.standalone.js)
+func ReDoc() fs.FS {
+	assets, _ := fs.Sub(redoc, "redoc")
+	return assets
+}
@@ -0,0 +1,105 @@
+// Package apperrors defines the domain errors services return. Handlers pass
+// them to the error middleware, which maps each kind to an HTTP status and a
+// stable machine-readable code.
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind classifies a domain error
+type Kind string
+
+const (
+	KindNotFound     Kind = "not_found"
+	KindConflict     Kind = "conflict"
+	KindValidation   Kind = "validation"
+	KindUnauthorized Kind = "unauthorized"
+	KindForbidden    Kind = "forbidden"
+	KindUnavailable  Kind = "unavailable"
+	KindInternal     Kind = "internal"
+)
+
+// Stable error codes returned to API clients in the "code" field
+const (
+	CodeNotFound         = "NOT_FOUND"
+	CodeConflict         = "CONFLICT"
+	CodeDuplicate        = "DUPLICATE_VALUE"
+	CodeValidation       = "VALIDATION_FAILED"
+	CodeInvalidReference = "INVALID_REFERENCE"
+	CodeConstraint       = "CONSTRAINT_VIOLATION"
+	CodeUnauthorized     = "UNAUTHORIZED"
+	CodeForbidden        = "FORBIDDEN"
+	CodeUnavailable      = "SERVICE_UNAVAILABLE"
+	CodeQueryTimeout     = "QUERY_TIMEOUT"
+	CodeRequestTimeout   = "REQUEST_TIMEOUT"
+	CodeInternal         = "INTERNAL_ERROR"
+)
+
+// Error is a domain error with a kind, a stable code and an optional field
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	// Field is the request field the error relates to, if known
+	Field string
+	// Err is the underlying cause; it is logged but never sent to clients
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NotFound reports a missing resource, e.g. NotFound("product")
+func NotFound(resource string) *Error {
+	return &Error{Kind: KindNotFound, Code: CodeNotFound, Message: resource + " not found"}
+}
+
+// Conflict reports a request that conflicts with the current state
+func Conflict(message, field string) *Error {
+	return &Error{Kind: KindConflict, Code: CodeConflict, Message: message, Field: field}
+}
+
+// Validation reports invalid input
+func Validation(message, field string) *Error {
+	return &Error{Kind: KindValidation, Code: CodeValidation, Message: message, Field: field}
+}
+
+// Unauthorized reports missing, invalid or expired credentials
+func Unauthorized(message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: CodeUnauthorized, Message: message}
+}
+
+// Forbidden reports an action the caller is not allowed to perform
+func Forbidden(message string) *Error {
+	return &Error{Kind: KindForbidden, Code: CodeForbidden, Message: message}
+}
+
+// Unavailable reports a dependency that cannot currently serve the request
+func Unavailable(message string, err error) *Error {
+	return &Error{Kind: KindUnavailable, Code: CodeUnavailable, Message: message, Err: err}
+}
+
+// As returns the domain error in err's chain, if any
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// IsNotFound reports whether err is a NotFound domain error
+func IsNotFound(err error) bool {
+	appErr, ok := As(err)
+	return ok && appErr.Kind == KindNotFound
+}
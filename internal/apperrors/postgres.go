@@ -0,0 +1,132 @@
+package apperrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// PostgreSQL error codes translated by FromDB
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgQueryCanceled       = "57014"
+)
+
+// detailKeyPattern extracts the column list from a constraint violation
+// detail such as `Key (sku)=(ABC-1) already exists.`
+var detailKeyPattern = regexp.MustCompile(`^Key \(([^)]+)\)=`)
+
+// FromDB translates integrity constraint violations from PostgreSQL into
+// domain errors naming the offending field, and query deadlines into
+// Unavailable errors. Any other error is returned unchanged, so callers can
+// wrap the result as usual.
+func FromDB(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return queryTimeout(err)
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	field := constraintField(pqErr)
+
+	switch pqErr.Code {
+	case pgUniqueViolation:
+		return &Error{
+			Kind:    KindConflict,
+			Code:    CodeDuplicate,
+			Message: fmt.Sprintf("%s already exists", describeField(field)),
+			Field:   field,
+			Err:     err,
+		}
+
+	case pgForeignKeyViolation:
+		// Deleting a row that is still referenced reports the referenced key
+		if strings.Contains(pqErr.Detail, "is still referenced") {
+			return &Error{
+				Kind:    KindConflict,
+				Code:    CodeConflict,
+				Message: fmt.Sprintf("%s is still referenced by %s", pqErr.Table, referencingTable(pqErr.Detail)),
+				Err:     err,
+			}
+		}
+		return &Error{
+			Kind:    KindValidation,
+			Code:    CodeInvalidReference,
+			Message: fmt.Sprintf("%s does not reference an existing record", describeField(field)),
+			Field:   field,
+			Err:     err,
+		}
+
+	case pgCheckViolation:
+		return &Error{
+			Kind:    KindValidation,
+			Code:    CodeConstraint,
+			Message: fmt.Sprintf("%s violates constraint %s", describeField(field), pqErr.Constraint),
+			Field:   field,
+			Err:     err,
+		}
+
+	case pgQueryCanceled:
+		// Sent when the driver cancels a query whose context expired, and for
+		// the server-side statement_timeout
+		return queryTimeout(err)
+	}
+
+	return err
+}
+
+func queryTimeout(err error) *Error {
+	return &Error{
+		Kind:    KindUnavailable,
+		Code:    CodeQueryTimeout,
+		Message: "The database did not respond in time",
+		Err:     err,
+	}
+}
+
+// constraintField derives the column name from the error detail, or from a
+// conventional constraint name (<table>_<column>_check) when there is none
+func constraintField(pqErr *pq.Error) string {
+	if pqErr.Column != "" {
+		return pqErr.Column
+	}
+
+	if m := detailKeyPattern.FindStringSubmatch(pqErr.Detail); m != nil {
+		// Expression indexes such as lower(email) report the expression
+		key := m[1]
+		if open := strings.Index(key, "("); open >= 0 {
+			key = strings.TrimSuffix(key[open+1:], ")")
+		}
+		return strings.ReplaceAll(key, " ", "")
+	}
+
+	name := pqErr.Constraint
+	name = strings.TrimPrefix(name, pqErr.Table+"_")
+	for _, suffix := range []string{"_check", "_key", "_fkey"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	return name
+}
+
+func describeField(field string) string {
+	if field == "" {
+		return "value"
+	}
+	return field
+}
+
+func referencingTable(detail string) string {
+	if _, table, ok := strings.Cut(detail, `from table "`); ok {
+		return strings.TrimSuffix(table, `".`)
+	}
+	return "other records"
+}
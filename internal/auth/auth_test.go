@@ -0,0 +1,173 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"ecom/internal/apperrors"
+	"ecom/internal/auth"
+	"ecom/internal/config"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	currentKey  = "2026-10:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	previousKey = "2026-04:ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA="
+)
+
+func newTokens(t *testing.T, ttl time.Duration, keys ...string) *auth.Tokens {
+	t.Helper()
+	tokens, err := auth.NewTokens(config.AuthConfig{
+		SigningKeys:     keys,
+		Issuer:          "ecom-test",
+		AccessTokenTTL:  ttl,
+		RefreshTokenTTL: 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens
+}
+
+func TestTokensRoundTrip(t *testing.T) {
+	tokens := newTokens(t, time.Minute, currentKey)
+	want := auth.Principal{Kind: auth.KindCustomer, ID: 42, Permissions: []auth.Permission{auth.PermOwnOrders}}
+
+	token, expiresAt, err := tokens.Issue(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Until(expiresAt); d <= 0 || d > time.Minute {
+		t.Errorf("expires in %s, want about a minute", d)
+	}
+	got, err := tokens.Verify(token)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Verify = %v, %v; want %v", got, err, want)
+	}
+}
+
+func TestTokensVerifyWithRotatedKeys(t *testing.T) {
+	staff := auth.Principal{Kind: auth.KindStaff, ID: 1}
+	old, _, err := newTokens(t, time.Minute, previousKey).Issue(staff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The new key signs; the previous one still verifies until removed
+	rotated := newTokens(t, time.Minute, currentKey, previousKey)
+	if got, err := rotated.Verify(old); err != nil || !reflect.DeepEqual(got, staff) {
+		t.Errorf("token of the previous key: %v, %v", got, err)
+	}
+	if _, err := newTokens(t, time.Minute, currentKey).Verify(old); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("token of a removed key: %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestTokensRejectInvalidTokens(t *testing.T) {
+	tokens := newTokens(t, time.Minute, currentKey)
+	valid, _, err := tokens.Issue(auth.Principal{Kind: auth.KindCustomer, ID: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, _, err := newTokens(t, -time.Minute, currentKey).Issue(auth.Principal{Kind: auth.KindCustomer, ID: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, rest, _ := strings.Cut(valid, ".")
+	payload, signature, _ := strings.Cut(rest, ".")
+
+	tests := map[string]string{
+		"expired":  expired,
+		"tampered": header + "." + payload + "x." + signature,
+		// {"alg":"none","typ":"JWT"} with the valid payload
+		"unsigned": "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0." + payload + ".",
+		"garbage":  "not-a-token",
+	}
+	for name, token := range tests {
+		if _, err := tokens.Verify(token); !errors.Is(err, auth.ErrInvalidToken) {
+			t.Errorf("%s: err = %v, want ErrInvalidToken", name, err)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	customer := auth.Principal{Kind: auth.KindCustomer, ID: 42, Permissions: []auth.Permission{auth.PermOwnOrders}}
+	support := auth.Principal{Kind: auth.KindStaff, ID: 1, Permissions: []auth.Permission{auth.PermOrdersRead}}
+	warehouse := auth.Principal{Kind: auth.KindStaff, ID: 2, Permissions: []auth.Permission{auth.PermStockAdjust}}
+	kind := func(err error) apperrors.Kind {
+		if err == nil {
+			return ""
+		}
+		appErr, ok := apperrors.As(err)
+		if !ok {
+			t.Fatalf("%v is not an apperrors.Error", err)
+		}
+		return appErr.Kind
+	}
+	as := func(p auth.Principal) context.Context { return auth.WithPrincipal(context.Background(), p) }
+
+	if got := kind(auth.Authorize(as(warehouse), auth.PermStockAdjust)); got != "" {
+		t.Errorf("granted permission: %s", got)
+	}
+	if got := kind(auth.Authorize(as(support), auth.PermStockAdjust)); got != apperrors.KindForbidden {
+		t.Errorf("missing permission: %s", got)
+	}
+	if got := kind(auth.Authorize(context.Background(), auth.PermStockAdjust)); got != apperrors.KindUnauthorized {
+		t.Errorf("anonymous: %s", got)
+	}
+
+	// Orders of customer 42
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		wanted apperrors.Kind
+	}{
+		{"owner", as(customer), ""},
+		{"other customer", as(auth.Principal{Kind: auth.KindCustomer, ID: 7, Permissions: customer.Permissions}), apperrors.KindForbidden},
+		{"owner without permission", as(auth.Principal{Kind: auth.KindCustomer, ID: 42}), apperrors.KindForbidden},
+		{"staff with permission", as(support), ""},
+		{"staff without permission", as(warehouse), apperrors.KindForbidden},
+		// Staff IDs are not customer IDs
+		{"staff with the owner's ID", as(auth.Principal{Kind: auth.KindStaff, ID: 42, Permissions: customer.Permissions}), apperrors.KindForbidden},
+		{"anonymous", context.Background(), apperrors.KindUnauthorized},
+	}
+	for _, tt := range tests {
+		if got := kind(auth.AuthorizeOwner(tt.ctx, 42, auth.PermOwnOrders, auth.PermOrdersRead)); got != tt.wanted {
+			t.Errorf("%s: %q, want %q", tt.name, got, tt.wanted)
+		}
+	}
+}
+
+func TestPasswords(t *testing.T) {
+	hash, err := auth.HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=65536,t=3,p=2$") {
+		t.Errorf("hash = %s, want a PHC argon2id hash", hash)
+	}
+	if ok, rehash, err := auth.VerifyPassword(hash, "correct horse"); !ok || rehash || err != nil {
+		t.Errorf("matching password: ok %v, rehash %v, err %v", ok, rehash, err)
+	}
+	if ok, _, err := auth.VerifyPassword(hash, "correct horse "); ok || err != nil {
+		t.Errorf("wrong password: ok %v, err %v", ok, err)
+	}
+
+	// bcrypt hashes verify and are upgraded
+	legacy, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, rehash, err := auth.VerifyPassword(string(legacy), "correct horse"); !ok || !rehash || err != nil {
+		t.Errorf("bcrypt password: ok %v, rehash %v, err %v", ok, rehash, err)
+	}
+
+	if _, _, err := auth.VerifyPassword("plaintext", "plaintext"); !errors.Is(err, auth.ErrUnknownHash) {
+		t.Errorf("unknown format: err = %v", err)
+	}
+}
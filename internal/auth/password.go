@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Argon2id parameters of new hashes (the RFC 9106 second recommendation).
+// Hashes record their parameters, so raising them only affects new hashes;
+// VerifyPassword reports older ones as needing a rehash.
+const (
+	argonMemory  = 64 * 1024 // KiB
+	argonTime    = 3
+	argonThreads = 2
+	argonSaltLen = 16
+	argonKeyLen  = 32
+)
+
+// MinPasswordLength is the shortest password accepted at registration
+const MinPasswordLength = 8
+
+// ErrUnknownHash is returned for a hash in neither supported format
+var ErrUnknownHash = errors.New("unsupported password hash format")
+
+// dummyHash is verified against when there is no account, so a login for an
+// unknown email takes as long as one with a wrong password. It is computed
+// on first use rather than when every binary importing auth starts.
+var dummyHash = sync.OnceValue(func() string {
+	hash, err := HashPassword("not a password")
+	if err != nil {
+		panic(err)
+	}
+	return hash
+})
+
+// HashPassword hashes password with argon2id in the PHC string format,
+// e.g. $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, argonSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, argonMemory, argonTime, argonThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// VerifyPassword reports whether password matches hash, an argon2id hash
+// from HashPassword or a bcrypt hash (e.g. imported accounts). needsRehash
+// is true for a match whose hash is bcrypt or uses weaker parameters than
+// HashPassword; callers then store a new hash while they have the password.
+func VerifyPassword(hash, password string) (ok, needsRehash bool, err error) {
+	if strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$") {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		}
+		if err != nil {
+			return false, false, err
+		}
+		return true, true, nil
+	}
+
+	var (
+		version          int
+		memory, passes   uint32
+		threads          uint8
+		encSalt, encHash string
+	)
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false, false, ErrUnknownHash
+	}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, false, ErrUnknownHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &passes, &threads); err != nil {
+		return false, false, ErrUnknownHash
+	}
+	encSalt, encHash = parts[4], parts[5]
+	salt, err := base64.RawStdEncoding.DecodeString(encSalt)
+	if err != nil {
+		return false, false, ErrUnknownHash
+	}
+	want, err := base64.RawStdEncoding.DecodeString(encHash)
+	if err != nil || len(want) == 0 {
+		return false, false, ErrUnknownHash
+	}
+
+	got := argon2.IDKey([]byte(password), salt, passes, memory, threads, uint32(len(want)))
+	if subtle.ConstantTimeCompare(got, want) != 1 {
+		return false, false, nil
+	}
+	weaker := memory < argonMemory || passes < argonTime || threads < argonThreads || len(want) < argonKeyLen
+	return true, weaker, nil
+}
+
+// VerifyNoPassword spends the time of a password check without an account,
+// so response times do not reveal which emails are registered
+func VerifyNoPassword(password string) {
+	_, _, _ = VerifyPassword(dummyHash(), password)
+}
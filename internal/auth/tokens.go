@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"ecom/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken is returned for an access token that is malformed,
+// expired, signed with an unknown key or otherwise not acceptable
+var ErrInvalidToken = errors.New("invalid or expired access token")
+
+// refreshTokenBytes is the entropy of a refresh token
+const refreshTokenBytes = 32
+
+// Tokens issues and verifies access tokens: HS256 JWTs whose kid header
+// names the signing key. The first configured key signs; tokens signed with
+// any configured key verify, so a new key can be put first while tokens of
+// the previous one expire.
+type Tokens struct {
+	keys       map[string][]byte
+	signingKID string
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+// claims are the claims of an access token. sub is the principal's ID.
+type claims struct {
+	Kind        Kind         `json:"kind"`
+	Permissions []Permission `json:"perms,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// NewTokens returns the token issuer configured by cfg, whose keys have been
+// checked by config.Load. Without keys it generates an ephemeral one:
+// tokens then stop verifying when the process restarts, which is fine in
+// development only.
+func NewTokens(cfg config.AuthConfig) (*Tokens, error) {
+	t := &Tokens{
+		keys:       make(map[string][]byte),
+		issuer:     cfg.Issuer,
+		accessTTL:  cfg.AccessTokenTTL,
+		refreshTTL: cfg.RefreshTokenTTL,
+		now:        time.Now,
+	}
+	for _, key := range cfg.SigningKeys {
+		kid, secret, err := config.ParseSigningKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if t.signingKID == "" {
+			t.signingKID = kid
+		}
+		t.keys[kid] = secret
+	}
+	if t.signingKID == "" {
+		secret := make([]byte, sha256.Size)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate signing key: %w", err)
+		}
+		t.signingKID = "ephemeral"
+		t.keys[t.signingKID] = secret
+	}
+	return t, nil
+}
+
+// AccessTTL is how long issued access tokens are valid
+func (t *Tokens) AccessTTL() time.Duration { return t.accessTTL }
+
+// RefreshTTL is how long issued refresh tokens are valid
+func (t *Tokens) RefreshTTL() time.Duration { return t.refreshTTL }
+
+// Issue returns a signed access token for p and when it expires
+func (t *Tokens) Issue(p Principal) (token string, expiresAt time.Time, err error) {
+	now := t.now()
+	expiresAt = now.Add(t.accessTTL)
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		Kind:        p.Kind,
+		Permissions: p.Permissions,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatInt(p.ID, 10),
+			Issuer:    t.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	jwtToken.Header["kid"] = t.signingKID
+	token, err = jwtToken.SignedString(t.keys[t.signingKID])
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign access token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// Verify checks an access token's signature, issuer and expiry and returns
+// the principal it names. Every failure is ErrInvalidToken, wrapping the
+// reason for logs.
+func (t *Tokens) Verify(token string) (Principal, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(token, &c, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		secret, ok := t.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		return secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(t.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(t.now),
+	)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	id, err := strconv.ParseInt(c.Subject, 10, 64)
+	if err != nil || id <= 0 || (c.Kind != KindCustomer && c.Kind != KindStaff) {
+		return Principal{}, fmt.Errorf("%w: unexpected subject %q of kind %q", ErrInvalidToken, c.Subject, c.Kind)
+	}
+	return Principal{Kind: c.Kind, ID: id, Permissions: c.Permissions}, nil
+}
+
+// NewRefreshToken returns a random refresh token for the client and the
+// hash to store in its place. A database leak then yields no usable tokens;
+// the tokens carry enough entropy that an unsalted hash is safe.
+func NewRefreshToken() (token string, hash []byte, err error) {
+	b := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken returns the stored form of a refresh token
+func HashRefreshToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
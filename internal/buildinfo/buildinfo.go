@@ -0,0 +1,57 @@
+// Package buildinfo reports what is running: the version and commit stamped
+// at build time and how long the process has been up.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Version and Commit are set at build time:
+//
+//	go build -ldflags "-X ecom/internal/buildinfo.Version=v1.2.0 -X ecom/internal/buildinfo.Commit=$(git rev-parse HEAD)" ./cmd/server
+//
+// Without them Commit falls back to the VCS revision Go records when
+// building from a checkout.
+var (
+	Version = "dev"
+	Commit  = ""
+)
+
+// started is when the process started serving, near enough
+var started = time.Now()
+
+// Info describes the running build
+type Info struct {
+	Version   string
+	Commit    string
+	GoVersion string
+	StartedAt time.Time
+	Uptime    time.Duration
+}
+
+// Get returns the running build's information
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Commit:    commit(),
+		GoVersion: runtime.Version(),
+		StartedAt: started,
+		Uptime:    time.Since(started),
+	}
+}
+
+func commit() string {
+	if Commit != "" {
+		return Commit
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				return setting.Value
+			}
+		}
+	}
+	return "unknown"
+}
@@ -3,76 +3,342 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
-// Config holds all configuration for the application
+// Config holds all configuration for the application. The yaml tags name
+// the settings in a config file (see Load).
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Env      string
+	Env        string           `yaml:"env"`
+	Server     ServerConfig     `yaml:"server"`
+	Database   DatabaseConfig   `yaml:"database"`
+	Migrations MigrationsConfig `yaml:"migrations"`
+	Docs       DocsConfig       `yaml:"docs"`
+	Log        LogConfig        `yaml:"log"`
+	Metrics    MetricsConfig    `yaml:"metrics"`
+	Tracing    TracingConfig    `yaml:"tracing"`
+	CORS       CORSConfig       `yaml:"cors"`
+	// AdminCORS applies to staff operations (e.g. catalog writes) instead
+	// of CORS
+	AdminCORS CORSConfig `yaml:"admin_cors"`
+	Auth      AuthConfig `yaml:"auth"`
 }
 
 // ServerConfig holds server-related configuration
 type ServerConfig struct {
-	Port        string
-	Host        string
-	Environment string
+	Port string `yaml:"port"`
+	Host string `yaml:"host"`
+	// Environment is a copy of Config.Env
+	Environment string `yaml:"-"`
+	// RequestTimeout bounds each API request; 0 disables it
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	// ReadTimeout, WriteTimeout and IdleTimeout configure http.Server; 0
+	// disables them
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	// ShutdownTimeout bounds the graceful shutdown, after which in-flight
+	// requests are dropped
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// ValidateOpenAPI logs traffic that violates docs/swagger.yaml; it is
+	// ignored in production
+	ValidateOpenAPI bool `yaml:"validate_openapi"`
 }
 
 // DatabaseConfig holds database-related configuration
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Name     string
-	SSLMode  string
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	SSLMode  string `yaml:"sslmode"`
+	// ApplicationName identifies the server's connections in
+	// pg_stat_activity; queries also carry the request ID in a comment
+	ApplicationName string `yaml:"application_name"`
+	// QueryTimeout bounds each repository call; 0 disables it
+	QueryTimeout time.Duration `yaml:"query_timeout"`
+	// MaxOpenConns caps the pool; 0 means unlimited
+	MaxOpenConns int `yaml:"max_open_conns"`
+	// MaxIdleConns is the number of connections kept open while idle; it
+	// may not exceed MaxOpenConns
+	MaxIdleConns int `yaml:"max_idle_conns"`
+	// ConnMaxLifetime and ConnMaxIdleTime retire old and unused
+	// connections; 0 keeps them forever
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
 }
 
-// LoadConfig loads configuration from environment variables
+// MigrationsConfig holds schema migration configuration
+type MigrationsConfig struct {
+	// Dir overrides the migrations embedded in the binary when set
+	Dir string `yaml:"dir"`
+	// OnStart applies pending migrations before the server starts serving
+	OnStart bool `yaml:"on_start"`
+}
+
+// DocsConfig holds API documentation configuration
+type DocsConfig struct {
+	// Enabled serves the spec, Swagger UI and ReDoc; it defaults to false in
+	// production
+	Enabled bool `yaml:"enabled"`
+	// Title is the page title of the documentation views
+	Title string `yaml:"title"`
+	// SpecPath is the URL path the OpenAPI spec is served at
+	SpecPath string `yaml:"spec_path"`
+	// EmbeddedSpec serves the spec compiled into the binary instead of
+	// reading docs/swagger.yaml from disk
+	EmbeddedSpec bool `yaml:"embedded_spec"`
+}
+
+// LogConfig holds logging configuration
+type LogConfig struct {
+	// Level is debug, info, warn or error
+	Level string `yaml:"level"`
+	// Format is json or text
+	Format string `yaml:"format"`
+}
+
+// MetricsConfig holds Prometheus metrics configuration
+type MetricsConfig struct {
+	// Enabled serves the metrics endpoint and records request metrics
+	Enabled bool `yaml:"enabled"`
+	// Path is the URL path the metrics are served at
+	Path string `yaml:"path"`
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration
+type TracingConfig struct {
+	// Exporter is none, otlp, stdout or file
+	Exporter string `yaml:"exporter"`
+	// OTLPEndpoint is the collector URL (e.g. http://localhost:4318); empty
+	// uses the standard OTEL_EXPORTER_OTLP_* variables
+	OTLPEndpoint string `yaml:"otlp_endpoint"`
+	// File receives the spans of the file exporter
+	File string `yaml:"file"`
+	// ServiceName identifies the server in traces
+	ServiceName string `yaml:"service_name"`
+	// SampleRatio is the fraction of new traces recorded (0 to 1)
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// CORSConfig holds a CORS policy: which cross-origin browser requests are
+// allowed
+type CORSConfig struct {
+	// AllowedOrigins are exact origins (https://shop.example.com), subdomain
+	// patterns (https://*.example.com) or * for any; empty allows none
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	AllowedMethods []string `yaml:"allowed_methods"`
+	AllowedHeaders []string `yaml:"allowed_headers"`
+	// ExposedHeaders are the response headers scripts may read
+	ExposedHeaders []string `yaml:"exposed_headers"`
+	// AllowCredentials lets browsers send cookies; it cannot be combined
+	// with the * origin
+	AllowCredentials bool `yaml:"allow_credentials"`
+	// MaxAge is how long browsers may cache a preflight response
+	MaxAge time.Duration `yaml:"max_age"`
+}
+
+// AuthConfig holds authentication configuration
+type AuthConfig struct {
+	// SigningKeys sign and verify access tokens, each "kid:secret" with a
+	// base64 secret of at least 32 bytes. The first key signs new tokens;
+	// the others only verify, so keys can be rotated without logging
+	// everyone out. The server requires them in production; elsewhere it
+	// generates an ephemeral key when none is set.
+	SigningKeys []string `yaml:"signing_keys"`
+	// Issuer is the iss claim of access tokens
+	Issuer string `yaml:"issuer"`
+	// AccessTokenTTL is how long an access token is accepted
+	AccessTokenTTL time.Duration `yaml:"access_token_ttl"`
+	// RefreshTokenTTL is how long a refresh token can be exchanged for new
+	// tokens; each refresh starts it again
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl"`
+}
+
+// LoadConfig loads configuration from the environment and the config file
+// named by CONFIG_FILE, if any. See Load.
 func LoadConfig() (*Config, error) {
+	return Load("")
+}
+
+// Load loads configuration from defaults, then the YAML or TOML file at path
+// (CONFIG_FILE when path is empty; none when both are), then environment
+// variables, each overriding the last. Variables in a .env file count as
+// set in the environment. Any variable can instead be read from the file
+// its _FILE variant names (e.g. DB_PASSWORD_FILE), for secrets mounted as
+// files.
+//
+// Invalid values do not fall back to defaults: every problem found is
+// reported at once in a *ValidationError.
+func Load(path string) (*Config, error) {
 	// Load .env file if it exists (ignore error if file doesn't exist)
 	_ = godotenv.Load()
 
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	l := &loader{}
+	if path != "" {
+		if err := l.readFile(path); err != nil {
+			return nil, err
+		}
+	}
+
 	config := &Config{
+		Env: "development",
 		Server: ServerConfig{
-			Port:        getEnv("PORT", "8080"),
-			Host:        getEnv("HOST", "localhost"),
-			Environment: getEnv("ENV", "development"),
+			Port:            "8080",
+			Host:            "localhost",
+			RequestTimeout:  10 * time.Second,
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    15 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 10 * time.Second,
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5433"),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "Postgres"),
-			Name:     getEnv("DB_NAME", "ecom"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Host:            "localhost",
+			Port:            "5433",
+			User:            "postgres",
+			Name:            "ecom",
+			SSLMode:         "disable",
+			ApplicationName: "ecom-api",
+			QueryTimeout:    5 * time.Second,
+			MaxOpenConns:    25,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+		},
+		Docs: DocsConfig{
+			Title:        "E-commerce API",
+			SpecPath:     "/api/v1/swagger.yaml",
+			EmbeddedSpec: true,
 		},
-		Env: getEnv("ENV", "development"),
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+			Path:    "/metrics",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			File:        "traces.jsonl",
+			ServiceName: "ecom-api",
+			SampleRatio: 1,
+		},
+		CORS:      defaultCORS(),
+		AdminCORS: defaultCORS(),
+		Auth: AuthConfig{
+			SigningKeys:     []string{},
+			Issuer:          "ecom-api",
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 30 * 24 * time.Hour,
+		},
+	}
+
+	l.string("env", "ENV", &config.Env)
+	config.Server.Environment = config.Env
+
+	l.string("server.port", "PORT", &config.Server.Port)
+	l.string("server.host", "HOST", &config.Server.Host)
+	l.duration("server.request_timeout", "REQUEST_TIMEOUT", &config.Server.RequestTimeout)
+	l.duration("server.read_timeout", "SERVER_READ_TIMEOUT", &config.Server.ReadTimeout)
+	l.duration("server.write_timeout", "SERVER_WRITE_TIMEOUT", &config.Server.WriteTimeout)
+	l.duration("server.idle_timeout", "SERVER_IDLE_TIMEOUT", &config.Server.IdleTimeout)
+	l.duration("server.shutdown_timeout", "SERVER_SHUTDOWN_TIMEOUT", &config.Server.ShutdownTimeout)
+	l.bool("server.validate_openapi", "OPENAPI_VALIDATE", &config.Server.ValidateOpenAPI)
+
+	l.string("database.host", "DB_HOST", &config.Database.Host)
+	l.string("database.port", "DB_PORT", &config.Database.Port)
+	l.string("database.user", "DB_USER", &config.Database.User)
+	l.string("database.password", "DB_PASSWORD", &config.Database.Password)
+	l.string("database.name", "DB_NAME", &config.Database.Name)
+	l.string("database.sslmode", "DB_SSLMODE", &config.Database.SSLMode)
+	l.string("database.application_name", "DB_APPLICATION_NAME", &config.Database.ApplicationName)
+	// DATABASE_URL replaces the connection settings above
+	var databaseURL string
+	if l.string("database.url", "DATABASE_URL", &databaseURL) {
+		if err := config.Database.applyURL(databaseURL); err != nil {
+			l.problem("DATABASE_URL (database.url): %v", err)
+		}
 	}
+	l.duration("database.query_timeout", "DB_QUERY_TIMEOUT", &config.Database.QueryTimeout)
+	l.int("database.max_open_conns", "DB_MAX_OPEN_CONNS", &config.Database.MaxOpenConns)
+	l.int("database.max_idle_conns", "DB_MAX_IDLE_CONNS", &config.Database.MaxIdleConns)
+	l.duration("database.conn_max_lifetime", "DB_CONN_MAX_LIFETIME", &config.Database.ConnMaxLifetime)
+	l.duration("database.conn_max_idle_time", "DB_CONN_MAX_IDLE_TIME", &config.Database.ConnMaxIdleTime)
+
+	l.string("migrations.dir", "MIGRATIONS_DIR", &config.Migrations.Dir)
+	l.bool("migrations.on_start", "MIGRATE_ON_START", &config.Migrations.OnStart)
+
+	config.Docs.Enabled = config.Env != "production"
+	l.bool("docs.enabled", "DOCS_ENABLED", &config.Docs.Enabled)
+	l.string("docs.title", "DOCS_TITLE", &config.Docs.Title)
+	l.string("docs.spec_path", "DOCS_SPEC_PATH", &config.Docs.SpecPath)
+	l.bool("docs.embedded_spec", "OPENAPI_EMBEDDED_SPEC", &config.Docs.EmbeddedSpec)
 
+	l.string("log.level", "LOG_LEVEL", &config.Log.Level)
+	l.string("log.format", "LOG_FORMAT", &config.Log.Format)
+
+	l.bool("metrics.enabled", "METRICS_ENABLED", &config.Metrics.Enabled)
+	l.string("metrics.path", "METRICS_PATH", &config.Metrics.Path)
+
+	l.string("tracing.exporter", "TRACING_EXPORTER", &config.Tracing.Exporter)
+	l.string("tracing.otlp_endpoint", "TRACING_OTLP_ENDPOINT", &config.Tracing.OTLPEndpoint)
+	l.string("tracing.file", "TRACING_FILE", &config.Tracing.File)
+	l.string("tracing.service_name", "OTEL_SERVICE_NAME", &config.Tracing.ServiceName)
+	l.float("tracing.sample_ratio", "TRACING_SAMPLE_RATIO", &config.Tracing.SampleRatio)
+
+	l.cors("cors", "CORS", &config.CORS)
+	l.cors("admin_cors", "CORS_ADMIN", &config.AdminCORS)
+
+	l.list("auth.signing_keys", "AUTH_SIGNING_KEYS", &config.Auth.SigningKeys)
+	l.string("auth.issuer", "AUTH_ISSUER", &config.Auth.Issuer)
+	l.duration("auth.access_token_ttl", "AUTH_ACCESS_TOKEN_TTL", &config.Auth.AccessTokenTTL)
+	l.duration("auth.refresh_token_ttl", "AUTH_REFRESH_TOKEN_TTL", &config.Auth.RefreshTokenTTL)
+
+	l.unknownKeys()
+	problems := append(l.problems, config.problems()...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
 	return config, nil
 }
 
+// defaultCORS allows no origins until some are configured
+func defaultCORS() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: []string{},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
+		ExposedHeaders: []string{"X-Request-ID"},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
 // GetDSN returns the database connection string
 func (c *Config) GetDSN() string {
 	return fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		c.Database.Host,
-		c.Database.Port,
-		c.Database.User,
-		c.Database.Password,
-		c.Database.Name,
-		c.Database.SSLMode,
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s application_name=%s",
+		dsnValue(c.Database.Host),
+		dsnValue(c.Database.Port),
+		dsnValue(c.Database.User),
+		dsnValue(c.Database.Password),
+		dsnValue(c.Database.Name),
+		dsnValue(c.Database.SSLMode),
+		dsnValue(c.Database.ApplicationName),
 	)
 }
 
-// getEnv gets an environment variable or returns a default value
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
+// dsnValue quotes a connection string value, so passwords with spaces or
+// quotes (and empty ones) survive
+func dsnValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
 }
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// loader reads settings from the environment and an optional config file,
+// collecting every problem instead of stopping at the first
+type loader struct {
+	path     string
+	file     map[string]string // flattened file settings, e.g. "server.port"
+	used     map[string]bool
+	problems []string
+}
+
+// readFile loads the YAML (.yaml, .yml) or TOML (.toml) config file at path.
+// Nested tables become dotted keys: server.port is port under server.
+func (l *loader) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var tree map[string]any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".toml":
+		err = toml.Unmarshal(data, &tree)
+	default:
+		return fmt.Errorf("unsupported config file %s: use .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	l.path = path
+	l.file = make(map[string]string)
+	l.used = make(map[string]bool)
+	l.flatten("", tree)
+	return nil
+}
+
+func (l *loader) flatten(prefix string, tree map[string]any) {
+	for name, value := range tree {
+		key := prefix + name
+		switch v := value.(type) {
+		case map[string]any:
+			l.flatten(key+".", v)
+		case []any:
+			// Lists read like their comma-separated variables
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			l.file[key] = strings.Join(items, ",")
+		case nil:
+			// An empty value leaves the setting alone, like an empty variable
+		default:
+			l.file[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// lookup returns the value of a setting and where it came from: the
+// environment variable env, the file named by env_FILE, or key in the config
+// file. Empty variables count as unset.
+func (l *loader) lookup(key, env string) (value, source string, ok bool) {
+	if l.used != nil {
+		l.used[key] = true
+	}
+
+	value = os.Getenv(env)
+	if secret := os.Getenv(env + "_FILE"); secret != "" {
+		if value != "" {
+			l.problem("%s and %s_FILE are both set; use one", env, env)
+			return "", "", false
+		}
+		data, err := os.ReadFile(secret)
+		if err != nil {
+			l.problem("%s_FILE: %v", env, err)
+			return "", "", false
+		}
+		// Secret files usually end in a newline that is not part of the value
+		return strings.TrimRight(string(data), "\r\n"), env + "_FILE", true
+	}
+	if value != "" {
+		return value, env, true
+	}
+
+	if value, ok := l.file[key]; ok {
+		return value, key + " in " + l.path, true
+	}
+	return "", "", false
+}
+
+// string sets *dst to the setting's value, if set, and reports whether it was
+func (l *loader) string(key, env string, dst *string) bool {
+	value, _, ok := l.lookup(key, env)
+	if ok {
+		*dst = value
+	}
+	return ok
+}
+
+// list splits a comma-separated value, e.g. "GET, POST", dropping blanks
+func (l *loader) list(key, env string, dst *[]string) {
+	value, _, ok := l.lookup(key, env)
+	if !ok {
+		return
+	}
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*dst = items
+}
+
+func (l *loader) bool(key, env string, dst *bool) {
+	value, source, ok := l.lookup(key, env)
+	if !ok {
+		return
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		l.problem("%s: %q is not a boolean (use true or false)", source, value)
+		return
+	}
+	*dst = parsed
+}
+
+func (l *loader) int(key, env string, dst *int) {
+	value, source, ok := l.lookup(key, env)
+	if !ok {
+		return
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		l.problem("%s: %q is not a whole number", source, value)
+		return
+	}
+	*dst = parsed
+}
+
+func (l *loader) float(key, env string, dst *float64) {
+	value, source, ok := l.lookup(key, env)
+	if !ok {
+		return
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		l.problem("%s: %q is not a number", source, value)
+		return
+	}
+	*dst = parsed
+}
+
+// duration accepts Go durations such as "5s" or "250ms"; "0" disables the
+// timeouts that allow it
+func (l *loader) duration(key, env string, dst *time.Duration) {
+	value, source, ok := l.lookup(key, env)
+	if !ok {
+		return
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		l.problem("%s: %q is not a duration (e.g. 5s, 250ms, 1m)", source, value)
+		return
+	}
+	*dst = parsed
+}
+
+// cors loads a CORS policy from the settings under section (file) and prefix
+// (environment), e.g. cors.max_age and CORS_MAX_AGE
+func (l *loader) cors(section, prefix string, dst *CORSConfig) {
+	l.list(section+".allowed_origins", prefix+"_ALLOWED_ORIGINS", &dst.AllowedOrigins)
+	l.list(section+".allowed_methods", prefix+"_ALLOWED_METHODS", &dst.AllowedMethods)
+	l.list(section+".allowed_headers", prefix+"_ALLOWED_HEADERS", &dst.AllowedHeaders)
+	l.list(section+".exposed_headers", prefix+"_EXPOSED_HEADERS", &dst.ExposedHeaders)
+	l.bool(section+".allow_credentials", prefix+"_ALLOW_CREDENTIALS", &dst.AllowCredentials)
+	l.duration(section+".max_age", prefix+"_MAX_AGE", &dst.MaxAge)
+}
+
+// unknownKeys reports config file settings nothing read, which are most
+// likely misspelt
+func (l *loader) unknownKeys() {
+	var unknown []string
+	for key := range l.file {
+		if !l.used[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		l.problem("%s in %s: unknown setting", key, l.path)
+	}
+}
+
+func (l *loader) problem(format string, args ...any) {
+	l.problems = append(l.problems, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,265 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError lists every invalid setting Load found
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// problems checks the loaded values against each other and their allowed
+// ranges. Settings are named by environment variable and config file key.
+func (c *Config) problems() []string {
+	var problems []string
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.Env != "", "ENV (env) must not be empty")
+
+	check(validPort(c.Server.Port), "PORT (server.port): %q is not a port number (1-65535)", c.Server.Port)
+	for _, timeout := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"REQUEST_TIMEOUT (server.request_timeout)", c.Server.RequestTimeout},
+		{"SERVER_READ_TIMEOUT (server.read_timeout)", c.Server.ReadTimeout},
+		{"SERVER_WRITE_TIMEOUT (server.write_timeout)", c.Server.WriteTimeout},
+		{"SERVER_IDLE_TIMEOUT (server.idle_timeout)", c.Server.IdleTimeout},
+		{"DB_QUERY_TIMEOUT (database.query_timeout)", c.Database.QueryTimeout},
+		{"DB_CONN_MAX_LIFETIME (database.conn_max_lifetime)", c.Database.ConnMaxLifetime},
+		{"DB_CONN_MAX_IDLE_TIME (database.conn_max_idle_time)", c.Database.ConnMaxIdleTime},
+	} {
+		check(timeout.value >= 0, "%s must not be negative", timeout.name)
+	}
+	check(c.Server.ShutdownTimeout > 0, "SERVER_SHUTDOWN_TIMEOUT (server.shutdown_timeout) must be positive")
+	// A request that runs out its timeout must still be able to write the
+	// 504, or the client sees the connection drop instead
+	if c.Server.RequestTimeout > 0 && c.Server.WriteTimeout > 0 {
+		check(c.Server.WriteTimeout > c.Server.RequestTimeout,
+			"SERVER_WRITE_TIMEOUT (server.write_timeout) %s must exceed REQUEST_TIMEOUT (server.request_timeout) %s",
+			c.Server.WriteTimeout, c.Server.RequestTimeout)
+	}
+
+	check(c.Database.Host != "", "DB_HOST (database.host) must not be empty")
+	check(validPort(c.Database.Port), "DB_PORT (database.port): %q is not a port number (1-65535)", c.Database.Port)
+	check(c.Database.User != "", "DB_USER (database.user) must not be empty")
+	check(c.Database.Name != "", "DB_NAME (database.name) must not be empty")
+	check(oneOf(c.Database.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full"),
+		"DB_SSLMODE (database.sslmode): %q is not one of disable, allow, prefer, require, verify-ca, verify-full", c.Database.SSLMode)
+	check(c.Database.MaxOpenConns >= 0, "DB_MAX_OPEN_CONNS (database.max_open_conns) must not be negative")
+	check(c.Database.MaxIdleConns >= 0, "DB_MAX_IDLE_CONNS (database.max_idle_conns) must not be negative")
+	// database/sql would silently lower it
+	if c.Database.MaxOpenConns > 0 {
+		check(c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+			"DB_MAX_IDLE_CONNS (database.max_idle_conns) %d must not exceed DB_MAX_OPEN_CONNS (database.max_open_conns) %d",
+			c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	}
+
+	check(strings.HasPrefix(c.Docs.SpecPath, "/"), "DOCS_SPEC_PATH (docs.spec_path): %q must start with /", c.Docs.SpecPath)
+
+	var level slog.Level
+	check(level.UnmarshalText([]byte(c.Log.Level)) == nil,
+		"LOG_LEVEL (log.level): %q is not one of debug, info, warn, error", c.Log.Level)
+	check(oneOf(strings.ToLower(c.Log.Format), "json", "text"),
+		"LOG_FORMAT (log.format): %q is not one of json, text", c.Log.Format)
+
+	check(strings.HasPrefix(c.Metrics.Path, "/"), "METRICS_PATH (metrics.path): %q must start with /", c.Metrics.Path)
+
+	check(oneOf(c.Tracing.Exporter, "none", "otlp", "stdout", "file"),
+		"TRACING_EXPORTER (tracing.exporter): %q is not one of none, otlp, stdout, file", c.Tracing.Exporter)
+	if c.Tracing.OTLPEndpoint != "" {
+		u, err := url.Parse(c.Tracing.OTLPEndpoint)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"TRACING_OTLP_ENDPOINT (tracing.otlp_endpoint): %q is not an http(s) URL", c.Tracing.OTLPEndpoint)
+	}
+	check(c.Tracing.Exporter != "file" || c.Tracing.File != "", "TRACING_FILE (tracing.file) must not be empty with the file exporter")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1,
+		"TRACING_SAMPLE_RATIO (tracing.sample_ratio): %v is not between 0 and 1", c.Tracing.SampleRatio)
+
+	problems = append(problems, c.CORS.problems("cors", "CORS")...)
+	problems = append(problems, c.AdminCORS.problems("admin_cors", "CORS_ADMIN")...)
+
+	kids := make(map[string]bool)
+	for i, key := range c.Auth.SigningKeys {
+		// Keys are named by position and kid: the secret must not be logged
+		kid, _, err := ParseSigningKey(key)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("AUTH_SIGNING_KEYS (auth.signing_keys) key %d: %v", i+1, err))
+			continue
+		}
+		check(!kids[kid], "AUTH_SIGNING_KEYS (auth.signing_keys): kid %q is used twice", kid)
+		kids[kid] = true
+	}
+	check(c.Auth.Issuer != "", "AUTH_ISSUER (auth.issuer) must not be empty")
+	check(c.Auth.AccessTokenTTL > 0, "AUTH_ACCESS_TOKEN_TTL (auth.access_token_ttl) must be positive")
+	check(c.Auth.RefreshTokenTTL > c.Auth.AccessTokenTTL,
+		"AUTH_REFRESH_TOKEN_TTL (auth.refresh_token_ttl) %s must exceed AUTH_ACCESS_TOKEN_TTL (auth.access_token_ttl) %s",
+		c.Auth.RefreshTokenTTL, c.Auth.AccessTokenTTL)
+
+	return problems
+}
+
+// problems checks a CORS policy loaded by loader.cors
+func (c *CORSConfig) problems(section, prefix string) []string {
+	var problems []string
+	for _, origin := range c.AllowedOrigins {
+		if err := checkOrigin(origin); err != nil {
+			problems = append(problems, fmt.Sprintf("%s_ALLOWED_ORIGINS (%s.allowed_origins): %q %v", prefix, section, origin, err))
+		}
+		if origin == "*" && c.AllowCredentials {
+			problems = append(problems, fmt.Sprintf("%s_ALLOWED_ORIGINS (%s.allowed_origins): * cannot be combined with %s_ALLOW_CREDENTIALS; list the origins", prefix, section, prefix))
+		}
+	}
+	for _, method := range c.AllowedMethods {
+		if method != strings.ToUpper(method) || strings.ContainsAny(method, " /") {
+			problems = append(problems, fmt.Sprintf("%s_ALLOWED_METHODS (%s.allowed_methods): %q is not an upper-case HTTP method", prefix, section, method))
+		}
+	}
+	if c.MaxAge < 0 {
+		problems = append(problems, fmt.Sprintf("%s_MAX_AGE (%s.max_age) must not be negative", prefix, section))
+	}
+	return problems
+}
+
+// checkOrigin accepts *, scheme://host[:port] and scheme://*.domain[:port]
+func checkOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(strings.Replace(origin, "://*.", "://wildcard.", 1))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("is not an origin such as https://shop.example.com")
+	}
+	if u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return fmt.Errorf("must not have a path, query or user; origins are scheme://host[:port]")
+	}
+	if strings.Contains(u.Host, "*") {
+		return fmt.Errorf("may only use * as the first label, e.g. https://*.example.com")
+	}
+	return nil
+}
+
+// minSigningKeyBytes is the shortest HMAC-SHA256 secret accepted, the size
+// of the hash
+const minSigningKeyBytes = 32
+
+// ParseSigningKey splits a "kid:secret" signing key and decodes its base64
+// secret. Errors never contain the secret.
+func ParseSigningKey(key string) (kid string, secret []byte, err error) {
+	kid, encoded, ok := strings.Cut(key, ":")
+	if !ok || kid == "" || encoded == "" {
+		return "", nil, fmt.Errorf("must be kid:secret, e.g. 2026-10:<base64 secret>")
+	}
+	secret, err = base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		secret, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("secret of kid %q is not base64", kid)
+	}
+	if len(secret) < minSigningKeyBytes {
+		return "", nil, fmt.Errorf("secret of kid %q has %d bytes; use at least %d (openssl rand -base64 32)", kid, len(secret), minSigningKeyBytes)
+	}
+	return kid, secret, nil
+}
+
+// applyURL sets the connection settings from a postgres:// URL. Only the
+// sslmode and application_name parameters are supported; the others GetDSN
+// does not pass on.
+func (d *DatabaseConfig) applyURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		// The error repeats the URL, password included
+		return fmt.Errorf("not a valid URL")
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return fmt.Errorf("scheme must be postgres://, got %q", u.Scheme+"://")
+	}
+
+	d.Host = u.Hostname()
+	d.Port = "5432"
+	if port := u.Port(); port != "" {
+		d.Port = port
+	}
+	d.User = u.User.Username()
+	d.Password, _ = u.User.Password()
+	d.Name = strings.TrimPrefix(u.Path, "/")
+
+	for name, values := range u.Query() {
+		switch name {
+		case "sslmode":
+			d.SSLMode = values[0]
+		case "application_name":
+			d.ApplicationName = values[0]
+		default:
+			return fmt.Errorf("unsupported parameter %q (use sslmode or application_name)", name)
+		}
+	}
+	return nil
+}
+
+// redacted replaces secrets in Redacted output
+const redacted = "REDACTED"
+
+// Redacted returns a copy of c without secrets, for display
+func (c *Config) Redacted() *Config {
+	out := *c
+	if out.Database.Password != "" {
+		out.Database.Password = redacted
+	}
+	if u, err := url.Parse(out.Tracing.OTLPEndpoint); err == nil && u.User != nil {
+		u.User = url.User(redacted)
+		out.Tracing.OTLPEndpoint = u.String()
+	}
+	if len(out.Auth.SigningKeys) > 0 {
+		keys := make([]string, len(out.Auth.SigningKeys))
+		for i, key := range out.Auth.SigningKeys {
+			kid, _, _ := strings.Cut(key, ":")
+			keys[i] = kid + ":" + redacted
+		}
+		out.Auth.SigningKeys = keys
+	}
+	return &out
+}
+
+// WriteYAML writes c in the config file format; Load reads it back
+func (c *Config) WriteYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n >= 1 && n <= 65535
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
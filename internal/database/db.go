@@ -9,37 +9,24 @@ import (
 	"ecom/internal/config"
 )
 
-var DB *sql.DB
-
-// Connect initializes the database connection
-func Connect(cfg *config.Config) error {
-	var err error
-
-	dsn := cfg.GetDSN()
-	DB, err = sql.Open("postgres", dsn)
+// Connect opens and verifies a connection pool sized by cfg.Database. The
+// caller owns the returned pool and is responsible for closing it.
+func Connect(cfg *config.Config) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.GetDSN())
 	if err != nil {
-		return fmt.Errorf("failed to open database connection: %w", err)
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
+	db.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime)
 
 	// Test the connection
-	if err = DB.Ping(); err != nil {
-		return fmt.Errorf("failed to ping database: %w", err)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
 	log.Println("✅ Database connected successfully")
-	return nil
-}
-
-// Close closes the database connection
-func Close() error {
-	if DB != nil {
-		return DB.Close()
-	}
-	return nil
-}
-
-// GetDB returns the database instance
-func GetDB() *sql.DB {
-	return DB
+	return db, nil
 }
-
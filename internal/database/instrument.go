@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+
+	"ecom/internal/requestid"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer names the spans of SQL statements; it is a no-op until tracing
+// sets the global tracer provider
+var tracer = otel.Tracer("ecom/internal/database")
+
+// affectedRowsKey records sql.Result.RowsAffected; semantic conventions
+// only define returned rows
+const affectedRowsKey = attribute.Key("db.response.affected_rows")
+
+// instrumentedQuerier runs each statement in a child span of the request
+// and prefixes it with a comment naming the request, so a slow or stuck
+// query in pg_stat_activity (or the server log) can be traced back to the
+// request's log lines
+type instrumentedQuerier struct {
+	q       sqlQuerier
+	comment string
+}
+
+// instrumented wraps q. The request ID is checked again because it ends up
+// inside the SQL text.
+func instrumented(ctx context.Context, q sqlQuerier) Querier {
+	var comment string
+	if id := requestid.FromContext(ctx); requestid.Valid(id) {
+		comment = "/* request_id=" + id + " */ "
+	}
+	return instrumentedQuerier{q: q, comment: comment}
+}
+
+func (i instrumentedQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startSpan(ctx, query)
+	result, err := i.q.ExecContext(ctx, i.comment+query, args...)
+	if err == nil {
+		if n, err := result.RowsAffected(); err == nil {
+			span.SetAttributes(affectedRowsKey.Int64(n))
+		}
+	}
+	endSpan(span, err)
+	return result, err
+}
+
+func (i instrumentedQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*Rows, error) {
+	ctx, span := startSpan(ctx, query)
+	rows, err := i.q.QueryContext(ctx, i.comment+query, args...)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	return &Rows{Rows: rows, span: span}, nil
+}
+
+func (i instrumentedQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) *Row {
+	ctx, span := startSpan(ctx, query)
+	return &Row{Row: i.q.QueryRowContext(ctx, i.comment+query, args...), span: span}
+}
+
+// Rows is *sql.Rows that counts the rows read. Close ends the statement's
+// span, so always close it.
+type Rows struct {
+	*sql.Rows
+	span  trace.Span
+	count int
+	ended bool
+}
+
+// Next prepares the next row, see sql.Rows.Next
+func (r *Rows) Next() bool {
+	if r.Rows.Next() {
+		r.count++
+		return true
+	}
+	return false
+}
+
+// Close closes the rows and ends the span with the number of rows read
+func (r *Rows) Close() error {
+	err := r.Rows.Close()
+	if !r.ended {
+		r.ended = true
+		r.span.SetAttributes(semconv.DBResponseReturnedRows(r.count))
+		endSpan(r.span, r.Rows.Err())
+	}
+	return err
+}
+
+// Row is *sql.Row whose Scan ends the statement's span, so always scan it
+type Row struct {
+	*sql.Row
+	span trace.Span
+}
+
+// Scan copies the row into dest, see sql.Row.Scan
+func (r *Row) Scan(dest ...any) error {
+	err := r.Row.Scan(dest...)
+	returned := 1
+	if err != nil {
+		returned = 0
+	}
+	r.span.SetAttributes(semconv.DBResponseReturnedRows(returned))
+	endSpan(r.span, err)
+	return err
+}
+
+func startSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation, collection := describe(query)
+	name := operation
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemNamePostgreSQL,
+		semconv.DBOperationName(operation),
+		semconv.DBQueryText(sanitize(query)),
+	}
+	if collection != "" {
+		name += " " + collection
+		attrs = append(attrs, semconv.DBCollectionName(collection))
+	}
+	return tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endSpan ends span, recording err unless it only means no row matched
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+var (
+	stringLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+	// Numbers not part of an identifier or a $n placeholder
+	numberLiteral = regexp.MustCompile(`(^|[^\w$.])\d+(?:\.\d+)?\b`)
+	whitespace    = regexp.MustCompile(`\s+`)
+	target        = regexp.MustCompile(`(?i)\b(?:from|into|update)\s+([a-z_][a-z0-9_.]*)`)
+)
+
+// sanitize replaces the literals in query with ? and collapses whitespace,
+// so span attributes never carry values. Repositories pass values as
+// parameters anyway; this guards against ones that do not.
+func sanitize(query string) string {
+	query = stringLiteral.ReplaceAllString(query, "?")
+	query = numberLiteral.ReplaceAllString(query, "${1}?")
+	return strings.TrimSpace(whitespace.ReplaceAllString(query, " "))
+}
+
+// describe returns the statement's operation (SELECT, INSERT, ...) and the
+// first table it reads or writes, for the span name
+func describe(query string) (operation, collection string) {
+	fields := strings.Fields(query)
+	if len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+	if m := target.FindStringSubmatch(query); m != nil {
+		collection = m[1]
+	}
+	return operation, collection
+}
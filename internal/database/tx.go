@@ -0,0 +1,188 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"ecom/internal/logging"
+
+	"github.com/lib/pq"
+)
+
+// PostgreSQL error codes after which a transaction can simply be run again
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+const (
+	// maxTxAttempts bounds how often a transaction is run when it keeps
+	// failing with a retryable error
+	maxTxAttempts = 3
+	// txRetryBackoff is the base delay before a retry; it doubles per attempt
+	// and is jittered so competing transactions do not collide again
+	txRetryBackoff = 20 * time.Millisecond
+)
+
+// Querier is the query surface repositories use. Its rows and row mirror
+// *sql.Rows and *sql.Row, and also end the query's trace span (see Conn).
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *Row
+}
+
+// sqlQuerier is the query surface shared by *sql.DB and *sql.Tx
+type sqlQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// TxManager runs units of work in a transaction
+type TxManager interface {
+	// WithinTx runs fn in a transaction carried by the context passed to fn.
+	// Repositories called with that context join the transaction. It commits
+	// when fn returns nil and rolls back otherwise.
+	//
+	// At the outermost level, serialization failures and deadlocks roll back
+	// and run fn again, so fn must not have side effects outside the
+	// database. Nested calls run in a savepoint instead: an error rolls back
+	// to the savepoint and is returned to the enclosing fn, which may handle
+	// it and carry on.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// txKey is the context key of the transaction in progress
+type txKey struct{}
+
+// txState is the transaction in progress and how deeply WithinTx is nested
+type txState struct {
+	tx    *sql.Tx
+	depth int
+}
+
+// txManager is the database/sql implementation of TxManager
+type txManager struct {
+	db   *sql.DB
+	opts *sql.TxOptions
+}
+
+// NewTxManager creates a transaction manager. opts sets the isolation level
+// of outermost transactions; nil uses the server default (READ COMMITTED).
+func NewTxManager(db *sql.DB, opts *sql.TxOptions) TxManager {
+	return &txManager{db: db, opts: opts}
+}
+
+// Conn returns the transaction carried by ctx, or db when there is none.
+// Repositories query through it so they join a unit of work transparently.
+// Each statement runs in a trace span and is tagged with the request ID
+// (see instrumented).
+func Conn(ctx context.Context, db *sql.DB) Querier {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return instrumented(ctx, state.tx)
+	}
+	return instrumented(ctx, db)
+}
+
+// WithinTx implements TxManager
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return withinSavepoint(ctx, state, fn)
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = m.run(ctx, fn)
+		if err == nil || !IsRetryable(err) || attempt == maxTxAttempts {
+			return err
+		}
+
+		backoff := txRetryBackoff << (attempt - 1)
+		backoff += rand.N(backoff)
+		logging.FromContext(ctx).Warn("retrying transaction",
+			slog.Int("attempt", attempt+1),
+			slog.Int("max_attempts", maxTxAttempts),
+			slog.Duration("backoff", backoff),
+			slog.Any("error", err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// run executes one attempt of an outermost transaction
+func (m *txManager) run(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	tx, err := m.db.BeginTx(ctx, m.opts)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, &txState{tx: tx})); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			logging.FromContext(ctx).Error("error rolling back transaction", slog.Any("error", rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// withinSavepoint runs a nested unit of work in a savepoint of the enclosing
+// transaction
+func withinSavepoint(ctx context.Context, parent *txState, fn func(ctx context.Context) error) (err error) {
+	state := &txState{tx: parent.tx, depth: parent.depth + 1}
+	name := fmt.Sprintf("sp_%d", state.depth)
+
+	if _, err := state.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = state.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		if _, rbErr := state.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			logging.FromContext(ctx).Error("error rolling back to savepoint", slog.String("savepoint", name), slog.Any("error", rbErr))
+		}
+		return err
+	}
+
+	if _, err := state.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
+
+// IsRetryable reports whether err is a serialization failure or deadlock,
+// after which the whole transaction can be run again
+func IsRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == pgSerializationFailure || pqErr.Code == pgDeadlockDetected
+}
@@ -38,10 +38,22 @@ type ListResponseData struct {
 }
 
 type ErrorResponseData struct {
-	Success   bool   `json:"success"`
-	Error     string `json:"error"`
-	Message   string `json:"message"`
-	Timestamp string `json:"timestamp"`
+	Success   bool         `json:"success"`
+	Error     string       `json:"error"`
+	Code      string       `json:"code"`
+	Field     string       `json:"field,omitempty"`
+	Details   []FieldError `json:"details,omitempty"`
+	Message   string       `json:"message"`
+	RequestID string       `json:"request_id,omitempty"`
+	Timestamp string       `json:"timestamp"`
+}
+
+// FieldError describes one failed validation rule on a request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
 }
 
 // ===========================
@@ -50,7 +62,7 @@ type ErrorResponseData struct {
 
 type CreateCategoryRequest struct {
 	Name        string `json:"name" binding:"required,max=100"`
-	Slug        string `json:"slug" binding:"required,max=100"`
+	Slug        string `json:"slug" binding:"required,max=100,slug"`
 	Description string `json:"description" binding:"max=5000"`
 	ParentID    *int64 `json:"parent_id,omitempty"`
 	ImageURL    string `json:"image_url" binding:"max=255"`
@@ -60,7 +72,7 @@ type CreateCategoryRequest struct {
 
 type UpdateCategoryRequest struct {
 	Name        string `json:"name" binding:"max=100"`
-	Slug        string `json:"slug" binding:"max=100"`
+	Slug        string `json:"slug" binding:"omitempty,max=100,slug"`
 	Description string `json:"description" binding:"max=5000"`
 	ParentID    *int64 `json:"parent_id"`
 	ImageURL    string `json:"image_url" binding:"max=255"`
@@ -73,9 +85,9 @@ type UpdateCategoryRequest struct {
 // ===========================
 
 type CreateProductRequest struct {
-	SKU                string   `json:"sku" binding:"required,max=50"`
+	SKU                string   `json:"sku" binding:"required,max=50,sku"`
 	Name               string   `json:"name" binding:"required,max=200"`
-	Slug               string   `json:"slug" binding:"required,max=200"`
+	Slug               string   `json:"slug" binding:"required,max=200,slug"`
 	Description        string   `json:"description" binding:"max=5000"`
 	ShortDescription   string   `json:"short_description" binding:"max=500"`
 	CategoryID         int64    `json:"category_id" binding:"required"`
@@ -87,7 +99,7 @@ type CreateProductRequest struct {
 	LowStockThreshold  int      `json:"low_stock_threshold" default:"10"`
 	WeightKg           *float64 `json:"weight_kg,omitempty"`
 	DimensionsCm       string   `json:"dimensions_cm" binding:"max=50"`
-	Barcode            string   `json:"barcode" binding:"max=100"`
+	Barcode            string   `json:"barcode" binding:"omitempty,max=100,barcode"`
 	Manufacturer       string   `json:"manufacturer" binding:"max=100"`
 	Brand              string   `json:"brand" binding:"max=100"`
 	IsFeautred         bool     `json:"is_featured" default:"false"`
@@ -97,14 +109,14 @@ type CreateProductRequest struct {
 
 type UpdateProductRequest struct {
 	Name               string   `json:"name" binding:"max=200"`
-	Slug               string   `json:"slug" binding:"max=200"`
+	Slug               string   `json:"slug" binding:"omitempty,max=200,slug"`
 	Description        string   `json:"description" binding:"max=5000"`
 	ShortDescription   string   `json:"short_description" binding:"max=500"`
 	CategoryID         *int64   `json:"category_id"`
-	Status             string   `json:"status" binding:"oneof=active inactive out_of_stock discontinued"`
-	Price              *float64 `json:"price" binding:"gt=0"`
+	Status             string   `json:"status" binding:"omitempty,oneof=active inactive out_of_stock discontinued"`
+	Price              *float64 `json:"price" binding:"omitempty,gt=0"`
 	CompareAtPrice     *float64 `json:"compare_at_price"`
-	StockQuantity      *int     `json:"stock_quantity" binding:"gte=0"`
+	StockQuantity      *int     `json:"stock_quantity" binding:"omitempty,gte=0"`
 	LowStockThreshold  *int     `json:"low_stock_threshold"`
 	WeightKg           *float64 `json:"weight_kg"`
 	DimensionsCm       string   `json:"dimensions_cm" binding:"max=50"`
@@ -168,11 +180,12 @@ type CreateOrderItemRequest struct {
 
 type CreateOrderRequest struct {
 	CustomerID         int64                     `json:"customer_id" binding:"required"`
-	Items              []CreateOrderItemRequest  `json:"items" binding:"required,min=1"`
+	Items              []CreateOrderItemRequest  `json:"items" binding:"required,min=1,dive"`
 	ShippingAddressID  *int64                    `json:"shipping_address_id"`
 	BillingAddressID   *int64                    `json:"billing_address_id"`
 	ShippingAmount     float64                   `json:"shipping_amount" binding:"gte=0" default:"0"`
 	DiscountAmount     float64                   `json:"discount_amount" binding:"gte=0" default:"0"`
+	Currency           string                    `json:"currency" binding:"omitempty,currency" default:"USD"`
 	Notes              string                    `json:"notes"`
 }
 
@@ -193,8 +206,33 @@ type CreatePaymentRequest struct {
 	OrderID       int64   `json:"order_id" binding:"required"`
 	PaymentMethod string  `json:"payment_method" binding:"required,oneof=credit_card debit_card paypal bank_transfer cash_on_delivery"`
 	Amount        float64 `json:"amount" binding:"required,gt=0"`
+	Currency      string  `json:"currency" binding:"omitempty,currency" default:"USD"`
 }
 
 type UpdatePaymentStatusRequest struct {
 	Status string `json:"status" binding:"required,oneof=pending processing completed failed refunded"`
 }
+
+// ===========================
+// Auth Request DTOs
+// ===========================
+
+// RegisterRequest creates a customer account with a password
+type RegisterRequest struct {
+	Email     string `json:"email" binding:"required,email,max=255" example:"jane@example.com"`
+	Password  string `json:"password" binding:"required,min=8,max=128" example:"correct horse battery"`
+	FirstName string `json:"first_name" binding:"required,max=100" example:"Jane"`
+	LastName  string `json:"last_name" binding:"required,max=100" example:"Doe"`
+	Phone     string `json:"phone" binding:"max=20"`
+}
+
+// LoginRequest exchanges an email and password for tokens
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email,max=255" example:"jane@example.com"`
+	Password string `json:"password" binding:"required,max=128" example:"correct horse battery"`
+}
+
+// RefreshTokenRequest carries a refresh token, to refresh or to log out
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required,max=128"`
+}
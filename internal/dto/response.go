@@ -163,3 +163,73 @@ type PaymentResponse struct {
 	CreatedAt       time.Time          `json:"created_at"`
 	UpdatedAt       time.Time          `json:"updated_at"`
 }
+
+// ===========================
+// Health Response DTOs
+// ===========================
+
+// LivenessResponse reports that the process is up
+type LivenessResponse struct {
+	Status string    `json:"status" example:"ok"`
+	Build  BuildInfo `json:"build"`
+}
+
+// ReadinessResponse reports the result of each dependency check; status is
+// "ready" when every check is "ok" and "unavailable" otherwise
+type ReadinessResponse struct {
+	Status string                 `json:"status" example:"ready"`
+	Checks map[string]CheckResult `json:"checks"`
+	Pool   PoolStats              `json:"pool"`
+	Build  BuildInfo              `json:"build"`
+}
+
+// CheckResult is the outcome of one readiness check
+type CheckResult struct {
+	Status     string  `json:"status" example:"ok"`
+	DurationMs float64 `json:"duration_ms" example:"1.25"`
+	Error      string  `json:"error,omitempty" example:"1 pending migration(s): 004_add_orders.sql"`
+}
+
+// PoolStats are the database connection pool statistics
+type PoolStats struct {
+	MaxOpen        int     `json:"max_open" example:"25"`
+	Open           int     `json:"open" example:"3"`
+	InUse          int     `json:"in_use" example:"1"`
+	Idle           int     `json:"idle" example:"2"`
+	WaitCount      int64   `json:"wait_count" example:"0"`
+	WaitDurationMs float64 `json:"wait_duration_ms" example:"0"`
+}
+
+// BuildInfo identifies the running build
+type BuildInfo struct {
+	Version       string    `json:"version" example:"v1.2.0"`
+	Commit        string    `json:"commit" example:"d02f705"`
+	GoVersion     string    `json:"go_version" example:"go1.24.0"`
+	StartedAt     time.Time `json:"started_at"`
+	UptimeSeconds float64   `json:"uptime_seconds" example:"3600"`
+}
+
+// ===========================
+// Auth Response DTOs
+// ===========================
+
+// TokenResponse is the token pair issued at login and on refresh. The access
+// token goes in "Authorization: Bearer" headers; the refresh token can be
+// exchanged once for a new pair before it expires.
+type TokenResponse struct {
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type" example:"Bearer"`
+	ExpiresIn        int       `json:"expires_in" example:"900"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// PrincipalResponse describes the authenticated caller. Permissions are
+// those its access token carries.
+type PrincipalResponse struct {
+	Kind        string   `json:"kind" example:"customer"`
+	ID          int64    `json:"id" example:"42"`
+	Email       string   `json:"email" example:"jane@example.com"`
+	Name        string   `json:"name" example:"Jane Doe"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ecom/internal/dto"
+	"ecom/internal/middleware"
+	"ecom/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	service *services.AuthService
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(service *services.AuthService) *AuthHandler {
+	return &AuthHandler{
+		service: service,
+	}
+}
+
+// Register godoc
+// @Summary Register a customer
+// @Description Create a customer account and log it in. Emails are case-insensitive.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RegisterRequest true "Account data"
+// @Success 201 {object} middleware.ApiResponse{data=dto.TokenResponse}
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 409 {object} middleware.ErrorApiResponse "Email already registered"
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
+// @Router /api/v1/auth/register [post]
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req dto.RegisterRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ValidationFailed(c, err)
+		return
+	}
+
+	tokens, err := h.service.Register(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	middleware.Created(c, tokens, "Registered successfully")
+}
+
+// Login godoc
+// @Summary Log a customer in
+// @Description Exchange a customer's email and password for an access token and a refresh token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.LoginRequest true "Credentials"
+// @Success 200 {object} middleware.ApiResponse{data=dto.TokenResponse}
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 401 {object} middleware.ErrorApiResponse "Invalid email or password"
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
+// @Router /api/v1/auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req dto.LoginRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ValidationFailed(c, err)
+		return
+	}
+
+	tokens, err := h.service.Login(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	middleware.OK(c, tokens, "Logged in successfully")
+}
+
+// StaffLogin godoc
+// @Summary Log a staff user in
+// @Description Exchange a staff user's email and password for tokens that authorize staff operations
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.LoginRequest true "Credentials"
+// @Success 200 {object} middleware.ApiResponse{data=dto.TokenResponse}
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 401 {object} middleware.ErrorApiResponse "Invalid email or password"
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
+// @Router /api/v1/auth/staff/login [post]
+func (h *AuthHandler) StaffLogin(c *gin.Context) {
+	var req dto.LoginRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ValidationFailed(c, err)
+		return
+	}
+
+	tokens, err := h.service.StaffLogin(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	middleware.OK(c, tokens, "Logged in successfully")
+}
+
+// Refresh godoc
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new token pair. Each refresh token can be used once; reusing one revokes every token of its login.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} middleware.ApiResponse{data=dto.TokenResponse}
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 401 {object} middleware.ErrorApiResponse "Invalid, expired, revoked or reused refresh token"
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
+// @Router /api/v1/auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ValidationFailed(c, err)
+		return
+	}
+
+	tokens, err := h.service.Refresh(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	middleware.OK(c, tokens, "Tokens refreshed successfully")
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description Revoke a refresh token and every token refreshed from the same login. Access tokens stay valid until they expire.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshTokenRequest true "Refresh token"
+// @Success 204 "Logged out"
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
+// @Router /api/v1/auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.ValidationFailed(c, err)
+		return
+	}
+
+	if err := h.service.Logout(c.Request.Context(), &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Me godoc
+// @Summary Get the authenticated caller
+// @Description Describe the customer or staff user the access token was issued to, with the permissions the token carries
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} middleware.ApiResponse{data=dto.PrincipalResponse}
+// @Failure 401 {object} middleware.ErrorApiResponse
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
+// @Router /api/v1/auth/me [get]
+func (h *AuthHandler) Me(c *gin.Context) {
+	principal, err := h.service.Me(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	middleware.OK(c, principal, "Caller retrieved successfully")
+}
@@ -4,7 +4,6 @@ import (
 	"net/http"
 	// "strconv"
 
-	"ecom/internal/database"
 	"ecom/internal/dto"
 	"ecom/internal/middleware"
 	"ecom/internal/services"
@@ -17,34 +16,39 @@ type CategoryHandler struct {
 }
 
 // NewCategoryHandler creates a new category handler
-func NewCategoryHandler() *CategoryHandler {
+func NewCategoryHandler(service *services.CategoryService) *CategoryHandler {
 	return &CategoryHandler{
-		service: services.NewCategoryService(database.GetDB()),
+		service: service,
 	}
 }
 
 // CreateCategory godoc
 // @Summary Create a new category
-// @Description Create a new product category
+// @Description Create a new product category. Requires catalog:write.
 // @Tags Categories
 // @Accept json
 // @Produce json
 // @Param request body dto.CreateCategoryRequest true "Category data"
+// @Security BearerAuth
 // @Success 201 {object} middleware.ApiResponse{data=dto.CategoryResponse}
-// @Failure 400 {object} middleware.ApiResponse
-// @Failure 500 {object} middleware.ApiResponse
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 401 {object} middleware.ErrorApiResponse
+// @Failure 403 {object} middleware.ErrorApiResponse "Missing permission"
+// @Failure 409 {object} middleware.ErrorApiResponse
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
 // @Router /api/v1/categories [post]
 func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	var req dto.CreateCategoryRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		middleware.BadRequest(c, err.Error(), "Validation failed")
+		middleware.ValidationFailed(c, err)
 		return
 	}
 
-	category, err := h.service.CreateCategory(&req)
+	category, err := h.service.CreateCategory(c.Request.Context(), &req)
 	if err != nil {
-		middleware.InternalError(c, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -57,18 +61,19 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 // @Tags Categories
 // @Accept json
 // @Produce json
-// @Param page query int false "Page number (default: 1)"
-// @Param limit query int false "Items per page (default: 10, max: 100)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (at most 100)" default(10)
 // @Success 200 {object} middleware.ListApiResponse{data=[]dto.CategoryResponse}
-// @Failure 400 {object} middleware.ApiResponse
-// @Failure 500 {object} middleware.ApiResponse
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
 // @Router /api/v1/categories [get]
 func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
 	page, limit := middleware.PaginationParams(c)
 
-	categories, total, err := h.service.GetAllCategories(page, limit)
+	categories, total, err := h.service.GetAllCategories(c.Request.Context(), page, limit)
 	if err != nil {
-		middleware.InternalError(c, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -88,9 +93,10 @@ func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Category ID"
 // @Success 200 {object} middleware.ApiResponse{data=dto.CategoryResponse}
-// @Failure 400 {object} middleware.ApiResponse
-// @Failure 404 {object} middleware.ApiResponse
-// @Failure 500 {object} middleware.ApiResponse
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 404 {object} middleware.ErrorApiResponse
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
 // @Router /api/v1/categories/{id} [get]
 func (h *CategoryHandler) GetCategory(c *gin.Context) {
 	categoryID, err := middleware.GetIDParam(c, "id")
@@ -99,13 +105,9 @@ func (h *CategoryHandler) GetCategory(c *gin.Context) {
 		return
 	}
 
-	category, err := h.service.GetCategoryByID(categoryID)
+	category, err := h.service.GetCategoryByID(c.Request.Context(), categoryID)
 	if err != nil {
-		if err.Error() == "category not found" {
-			middleware.NotFound(c, "Category not found")
-			return
-		}
-		middleware.InternalError(c, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -114,16 +116,21 @@ func (h *CategoryHandler) GetCategory(c *gin.Context) {
 
 // UpdateCategory godoc
 // @Summary Update category
-// @Description Update an existing product category
+// @Description Update an existing product category. Requires catalog:write.
 // @Tags Categories
 // @Accept json
 // @Produce json
 // @Param id path int true "Category ID"
 // @Param request body dto.UpdateCategoryRequest true "Category data"
+// @Security BearerAuth
 // @Success 200 {object} middleware.ApiResponse{data=dto.CategoryResponse}
-// @Failure 400 {object} middleware.ApiResponse
-// @Failure 404 {object} middleware.ApiResponse
-// @Failure 500 {object} middleware.ApiResponse
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 401 {object} middleware.ErrorApiResponse
+// @Failure 403 {object} middleware.ErrorApiResponse "Missing permission"
+// @Failure 404 {object} middleware.ErrorApiResponse
+// @Failure 409 {object} middleware.ErrorApiResponse
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
 // @Router /api/v1/categories/{id} [put]
 func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 	categoryID, err := middleware.GetIDParam(c, "id")
@@ -134,17 +141,13 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 
 	var req dto.UpdateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		middleware.BadRequest(c, err.Error(), "Validation failed")
+		middleware.ValidationFailed(c, err)
 		return
 	}
 
-	category, err := h.service.UpdateCategory(categoryID, &req)
+	category, err := h.service.UpdateCategory(c.Request.Context(), categoryID, &req)
 	if err != nil {
-		if err.Error() == "category not found" {
-			middleware.NotFound(c, "Category not found")
-			return
-		}
-		middleware.InternalError(c, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -153,15 +156,19 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 
 // DeleteCategory godoc
 // @Summary Delete category
-// @Description Delete a product category (soft delete)
+// @Description Delete a product category (soft delete). Requires catalog:write.
 // @Tags Categories
 // @Accept json
 // @Produce json
 // @Param id path int true "Category ID"
+// @Security BearerAuth
 // @Success 200 {object} middleware.ApiResponse
-// @Failure 400 {object} middleware.ApiResponse
-// @Failure 404 {object} middleware.ApiResponse
-// @Failure 500 {object} middleware.ApiResponse
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 401 {object} middleware.ErrorApiResponse
+// @Failure 403 {object} middleware.ErrorApiResponse "Missing permission"
+// @Failure 404 {object} middleware.ErrorApiResponse
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
 // @Router /api/v1/categories/{id} [delete]
 func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 	categoryID, err := middleware.GetIDParam(c, "id")
@@ -170,13 +177,9 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	err = h.service.DeleteCategory(categoryID)
+	err = h.service.DeleteCategory(c.Request.Context(), categoryID)
 	if err != nil {
-		if err.Error() == "category not found" {
-			middleware.NotFound(c, "Category not found")
-			return
-		}
-		middleware.InternalError(c, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -190,12 +193,13 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Category ID"
-// @Param page query int false "Page number (default: 1)"
-// @Param limit query int false "Items per page (default: 10, max: 100)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (at most 100)" default(10)
 // @Success 200 {object} middleware.ListApiResponse{data=[]dto.ProductResponse}
-// @Failure 400 {object} middleware.ApiResponse
-// @Failure 404 {object} middleware.ApiResponse
-// @Failure 500 {object} middleware.ApiResponse
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 404 {object} middleware.ErrorApiResponse
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
 // @Router /api/v1/categories/{id}/products [get]
 func (h *CategoryHandler) GetCategoryProducts(c *gin.Context) {
 	categoryID, err := middleware.GetIDParam(c, "id")
@@ -205,21 +209,17 @@ func (h *CategoryHandler) GetCategoryProducts(c *gin.Context) {
 	}
 
 	// Verify category exists
-	_, err = h.service.GetCategoryByID(categoryID)
+	_, err = h.service.GetCategoryByID(c.Request.Context(), categoryID)
 	if err != nil {
-		if err.Error() == "category not found" {
-			middleware.NotFound(c, "Category not found")
-			return
-		}
-		middleware.InternalError(c, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
 	page, limit := middleware.PaginationParams(c)
 
-	products, total, err := h.service.GetProductsByCategory(categoryID, page, limit)
+	products, total, err := h.service.GetProductsByCategory(c.Request.Context(), categoryID, page, limit)
 	if err != nil {
-		middleware.InternalError(c, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
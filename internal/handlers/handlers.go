@@ -4,10 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
-	"time"
 
-	"ecom/internal/database"
-	"ecom/internal/models"
 	"ecom/internal/services"
 )
 
@@ -18,26 +15,13 @@ type Handler struct {
 }
 
 // NewHandler creates a new handler instance
-func NewHandler() *Handler {
+func NewHandler(userService *services.UserService, productService *services.ProductService) *Handler {
 	return &Handler{
-		userService:    services.NewUserService(),
-		productService: services.NewProductService(database.GetDB()),
+		userService:    userService,
+		productService: productService,
 	}
 }
 
-// HealthCheck handles the health check endpoint
-func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
-	response := models.HealthCheck{
-		Status:    "healthy",
-		Message:   "Server is running",
-		Timestamp: time.Now().Format(time.RFC3339),
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
-}
-
 // GetUser handles GET /api/users/:id
 func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
 	// Extract user ID from URL path
@@ -49,7 +33,7 @@ func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.userService.GetUser(id)
+	user, err := h.userService.GetUser(r.Context(), id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
@@ -64,7 +48,7 @@ func (h *Handler) GetAllProducts(w http.ResponseWriter, r *http.Request) {
 	page := 1
 	limit := 10
 	
-	products, _, err := h.productService.GetAllProducts(page, limit)
+	products, _, err := h.productService.GetAllProducts(r.Context(), page, limit)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
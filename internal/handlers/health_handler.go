@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ecom/internal/buildinfo"
+	"ecom/internal/dto"
+	"ecom/internal/middleware"
+	"ecom/pkg/migrate"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds all readiness checks together, well within the
+// probe's own timeout
+const readinessTimeout = 2 * time.Second
+
+// Check names and outcomes reported by Ready
+const (
+	checkDatabase   = "database"
+	checkMigrations = "migrations"
+
+	checkOK     = "ok"
+	checkFailed = "failed"
+)
+
+type HealthHandler struct {
+	db         *sql.DB
+	migrations *migrate.MigrationRunner
+}
+
+// NewHealthHandler creates a new health handler. migrations may be nil to
+// skip the pending-migrations check.
+func NewHealthHandler(db *sql.DB, migrations *migrate.MigrationRunner) *HealthHandler {
+	return &HealthHandler{
+		db:         db,
+		migrations: migrations,
+	}
+}
+
+// Live godoc
+// @Summary Liveness probe
+// @Description Reports that the process is running and serving HTTP. It checks no dependencies, so a database outage does not get the pod restarted; see /health/ready.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} middleware.ApiResponse{data=dto.LivenessResponse}
+// @Router /health/live [get]
+// @Router /health [get]
+// @Router /api/health [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	middleware.OK(c, dto.LivenessResponse{
+		Status: "ok",
+		Build:  buildInfo(),
+	}, "Server is running")
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description Reports whether the service can accept traffic: the database answers a ping and no schema migrations are pending. Returns 503 with the result of each check when one fails. Checks time out after 2 seconds.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} middleware.ApiResponse{data=dto.ReadinessResponse}
+// @Failure 503 {object} middleware.ApiResponse{data=dto.ReadinessResponse}
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	report := dto.ReadinessResponse{
+		Status: "ready",
+		Checks: make(map[string]dto.CheckResult),
+		Build:  buildInfo(),
+	}
+
+	database := runCheck(func() error { return h.db.PingContext(ctx) })
+	report.Checks[checkDatabase] = database
+
+	if h.migrations != nil {
+		if database.Status == checkOK {
+			report.Checks[checkMigrations] = runCheck(func() error { return h.checkMigrations(ctx) })
+		} else {
+			report.Checks[checkMigrations] = dto.CheckResult{Status: checkFailed, Error: "skipped: database unavailable"}
+		}
+	}
+
+	stats := h.db.Stats()
+	report.Pool = dto.PoolStats{
+		MaxOpen:        stats.MaxOpenConnections,
+		Open:           stats.OpenConnections,
+		InUse:          stats.InUse,
+		Idle:           stats.Idle,
+		WaitCount:      stats.WaitCount,
+		WaitDurationMs: float64(stats.WaitDuration.Microseconds()) / 1000,
+	}
+
+	var failed []string
+	for name, check := range report.Checks {
+		if check.Status != checkOK {
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		report.Status = "unavailable"
+		middleware.ServiceUnavailableWithData(c, report, "Service is not ready: "+strings.Join(failed, ", ")+" failed")
+		return
+	}
+
+	middleware.OK(c, report, "Service is ready")
+}
+
+// checkMigrations fails while migrations are pending
+func (h *HealthHandler) checkMigrations(ctx context.Context) error {
+	pending, err := h.migrations.Pending(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to check migrations: %w", err)
+	}
+	if len(pending) > 0 {
+		files := make([]string, 0, len(pending))
+		for _, mig := range pending {
+			files = append(files, mig.UpFile)
+		}
+		return fmt.Errorf("%d pending migration(s): %s", len(pending), strings.Join(files, ", "))
+	}
+	return nil
+}
+
+// runCheck times check and reports its outcome
+func runCheck(check func() error) dto.CheckResult {
+	start := time.Now()
+	err := check()
+	result := dto.CheckResult{
+		Status:     checkOK,
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = checkFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func buildInfo() dto.BuildInfo {
+	info := buildinfo.Get()
+	return dto.BuildInfo{
+		Version:       info.Version,
+		Commit:        info.Commit,
+		GoVersion:     info.GoVersion,
+		StartedAt:     info.StartedAt.UTC(),
+		UptimeSeconds: info.Uptime.Round(time.Second).Seconds(),
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"net/http"
 	"strconv"
 
-	"ecom/internal/database"
 	"ecom/internal/dto"
 	"ecom/internal/middleware"
 	"ecom/internal/services"
@@ -17,34 +16,39 @@ type ProductHandler struct {
 }
 
 // NewProductHandler creates a new product handler
-func NewProductHandler() *ProductHandler {
+func NewProductHandler(service *services.ProductService) *ProductHandler {
 	return &ProductHandler{
-		service: services.NewProductService(database.GetDB()),
+		service: service,
 	}
 }
 
 // CreateProduct godoc
 // @Summary Create a new product
-// @Description Create a new product in the catalog
+// @Description Create a new product in the catalog, with its opening stock. Requires catalog:write.
 // @Tags Products
 // @Accept json
 // @Produce json
 // @Param request body dto.CreateProductRequest true "Product data"
+// @Security BearerAuth
 // @Success 201 {object} middleware.ApiResponse{data=dto.ProductResponse}
-// @Failure 400 {object} middleware.ApiResponse
-// @Failure 500 {object} middleware.ApiResponse
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 401 {object} middleware.ErrorApiResponse
+// @Failure 403 {object} middleware.ErrorApiResponse "Missing permission"
+// @Failure 409 {object} middleware.ErrorApiResponse
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
 // @Router /api/v1/products [post]
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	var req dto.CreateProductRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		middleware.BadRequest(c, err.Error(), "Validation failed")
+		middleware.ValidationFailed(c, err)
 		return
 	}
 	
-	product, err := h.service.CreateProduct(&req)
+	product, err := h.service.CreateProduct(c.Request.Context(), &req)
 	if err != nil {
-		middleware.InternalError(c, "Failed to create product")
+		_ = c.Error(err)
 		return
 	}
 
@@ -57,18 +61,19 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 // @Tags Products
 // @Accept json
 // @Produce json
-// @Param page query int false "Page number (default: 1)"
-// @Param limit query int false "Items per page (default: 10, max: 100)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (at most 100)" default(10)
 // @Success 200 {object} middleware.ListApiResponse{data=[]dto.ProductResponse}
-// @Failure 400 {object} middleware.ApiResponse
-// @Failure 500 {object} middleware.ApiResponse
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
 // @Router /api/v1/products [get]
 func (h *ProductHandler) GetAllProducts(c *gin.Context) {
 	page, limit := middleware.PaginationParams(c)
 
-	products, total, err := h.service.GetAllProducts(page, limit)
+	products, total, err := h.service.GetAllProducts(c.Request.Context(), page, limit)
 	if err != nil {
-		middleware.InternalError(c, "Failed to retrieve products")
+		_ = c.Error(err)
 		return
 	}
 
@@ -88,8 +93,10 @@ func (h *ProductHandler) GetAllProducts(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Product ID"
 // @Success 200 {object} middleware.ApiResponse{data=dto.ProductResponse}
-// @Failure 404 {object} middleware.ApiResponse
-// @Failure 500 {object} middleware.ApiResponse
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 404 {object} middleware.ErrorApiResponse
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
 // @Router /api/v1/products/{id} [get]
 func (h *ProductHandler) GetProduct(c *gin.Context) {
 	productID, err := middleware.GetIDParam(c, "id")
@@ -98,13 +105,9 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		return
 	}
 
-	product, err := h.service.GetProductByID(productID)
+	product, err := h.service.GetProductByID(c.Request.Context(), productID)
 	if err != nil {
-		if err.Error() == "product not found" {
-			middleware.NotFound(c, "Product not found")
-			return
-		}
-		middleware.InternalError(c, "Failed to retrieve product")
+		_ = c.Error(err)
 		return
 	}
 
@@ -113,16 +116,21 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 
 // UpdateProduct godoc
 // @Summary Update product
-// @Description Update an existing product
+// @Description Update an existing product. Changing stock_quantity requires stock:adjust; changing anything else requires catalog:write.
 // @Tags Products
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
 // @Param request body dto.UpdateProductRequest true "Product data"
+// @Security BearerAuth
 // @Success 200 {object} middleware.ApiResponse{data=dto.ProductResponse}
-// @Failure 400 {object} middleware.ApiResponse
-// @Failure 404 {object} middleware.ApiResponse
-// @Failure 500 {object} middleware.ApiResponse
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 401 {object} middleware.ErrorApiResponse
+// @Failure 403 {object} middleware.ErrorApiResponse "Missing permission"
+// @Failure 404 {object} middleware.ErrorApiResponse
+// @Failure 409 {object} middleware.ErrorApiResponse
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
 // @Router /api/v1/products/{id} [put]
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	productID, err := middleware.GetIDParam(c, "id")
@@ -133,17 +141,13 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 
 	var req dto.UpdateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		middleware.BadRequest(c, err.Error(), "Validation failed")
+		middleware.ValidationFailed(c, err)
 		return
 	}
 
-	product, err := h.service.UpdateProduct(productID, &req)
+	product, err := h.service.UpdateProduct(c.Request.Context(), productID, &req)
 	if err != nil {
-		if err.Error() == "product not found" {
-			middleware.NotFound(c, "Product not found")
-			return
-		}
-		middleware.InternalError(c, "Failed to update product")
+		_ = c.Error(err)
 		return
 	}
 
@@ -152,14 +156,19 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 
 // DeleteProduct godoc
 // @Summary Delete product
-// @Description Delete a product (soft delete)
+// @Description Delete a product (soft delete). Requires catalog:write.
 // @Tags Products
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
+// @Security BearerAuth
 // @Success 204
-// @Failure 404 {object} middleware.ApiResponse
-// @Failure 500 {object} middleware.ApiResponse
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 401 {object} middleware.ErrorApiResponse
+// @Failure 403 {object} middleware.ErrorApiResponse "Missing permission"
+// @Failure 404 {object} middleware.ErrorApiResponse
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
 // @Router /api/v1/products/{id} [delete]
 func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	productID, err := middleware.GetIDParam(c, "id")
@@ -168,13 +177,9 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 		return
 	}
 
-	err = h.service.DeleteProduct(productID)
+	err = h.service.DeleteProduct(c.Request.Context(), productID)
 	if err != nil {
-		if err.Error() == "product not found" {
-			middleware.NotFound(c, "Product not found")
-			return
-		}
-		middleware.InternalError(c, "Failed to delete product")
+		_ = c.Error(err)
 		return
 	}
 
@@ -190,11 +195,12 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param category_id path int true "Category ID"
-// @Param page query int false "Page number (default: 1)"
-// @Param size query int false "Page size (default: 10)"
-// @Success 200 {object} middleware.ApiResponse{data=[]dto.ProductResponse}
-// @Failure 400 {object} middleware.ApiResponse
-// @Failure 500 {object} middleware.ApiResponse
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (at most 100)" default(10)
+// @Success 200 {object} middleware.ListApiResponse{data=[]dto.ProductResponse}
+// @Failure 400 {object} middleware.ErrorApiResponse
+// @Failure 500 {object} middleware.ErrorApiResponse
+// @Failure default {object} middleware.ErrorApiResponse
 // @Router /api/v1/products/category/{category_id} [get]
 func (h *ProductHandler) GetProductsByCategoryID(c *gin.Context) {
 	categoryIDStr := c.Param("category_id")
@@ -206,9 +212,9 @@ func (h *ProductHandler) GetProductsByCategoryID(c *gin.Context) {
 
 	page, limit := middleware.PaginationParams(c)
 
-	products, total, err := h.service.GetAllProductsByCategory(categoryID, page, limit)
+	products, total, err := h.service.GetAllProductsByCategory(c.Request.Context(), categoryID, page, limit)
 	if err != nil {
-		middleware.InternalError(c, "Failed to retrieve products")
+		_ = c.Error(err)
 		return
 	}
 
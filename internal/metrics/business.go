@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Stock movement types, as recorded in inventory_movements.movement_type
+const (
+	StockPurchase   = "purchase"
+	StockSale       = "sale"
+	StockAdjustment = "adjustment"
+	StockReturn     = "return"
+	StockDamage     = "damage"
+)
+
+// Business counts domain events. Its methods do nothing on a nil *Business,
+// so services built without metrics (tests, tools) need no special casing.
+// Record events after the transaction that made them commits, so retried or
+// rolled back work is not counted.
+type Business struct {
+	stockMovements *prometheus.CounterVec
+	stockUnits     *prometheus.CounterVec
+}
+
+func newBusiness() *Business {
+	return &Business{
+		stockMovements: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "stock_movements_total",
+			Help:      "Stock movements by type.",
+		}, []string{"type"}),
+		stockUnits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "stock_movement_units_total",
+			Help:      "Units moved in or out of stock by movement type.",
+		}, []string{"type"}),
+	}
+}
+
+func (b *Business) register(registry *prometheus.Registry) {
+	registry.MustRegister(b.stockMovements, b.stockUnits)
+}
+
+// StockMoved counts a stock movement of quantity units, in either direction
+func (b *Business) StockMoved(movementType string, quantity int) {
+	if b == nil || quantity == 0 {
+		return
+	}
+	if quantity < 0 {
+		quantity = -quantity
+	}
+	b.stockMovements.WithLabelValues(movementType).Inc()
+	b.stockUnits.WithLabelValues(movementType).Add(float64(quantity))
+}
@@ -0,0 +1,124 @@
+// Package metrics exposes the server's Prometheus metrics: HTTP latency by
+// route template and status, connection pool statistics, the schema version
+// and business counters. Collectors live in their own registry, so tests can
+// scrape Handler without a Prometheus server or global state.
+package metrics
+
+import (
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the application's own metrics
+const namespace = "ecom"
+
+// unmatchedRoute labels requests that matched no route, so unknown paths
+// cannot create new series
+const unmatchedRoute = "unmatched"
+
+// Metrics holds the collectors and the registry they are served from
+type Metrics struct {
+	registry     *prometheus.Registry
+	httpDuration *prometheus.HistogramVec
+
+	// Business counts domain events; services record them through it
+	Business *Business
+}
+
+// New creates the HTTP and business metrics along with the Go runtime and
+// process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of HTTP requests by method, route template and status.",
+			Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"method", "route", "status"}),
+		Business: newBusiness(),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpDuration,
+	)
+	m.Business.register(m.registry)
+	return m
+}
+
+// RegisterDB exports the pool statistics of db (open and in-use
+// connections, wait count and duration, ...) as go_sql_* metrics
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// VersionSource reports the current schema version; *migrate.MigrationRunner
+// implements it
+type VersionSource interface {
+	Version() (uint64, error)
+}
+
+// RegisterMigrations exports the latest applied migration version. It is
+// read on each scrape and left out of the scrape when it cannot be read.
+func (m *Metrics) RegisterMigrations(source VersionSource) {
+	m.registry.MustRegister(&migrationCollector{
+		source: source,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "schema", "migration_version"),
+			"Latest applied schema migration version.",
+			nil, nil,
+		),
+	})
+}
+
+// Handler serves the registry in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Middleware observes the duration of each request. It is labelled with the
+// route template (e.g. /api/v1/products/:id), never the raw path.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		m.httpDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// migrationCollector reads the schema version at scrape time
+type migrationCollector struct {
+	source VersionSource
+	desc   *prometheus.Desc
+}
+
+func (c *migrationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *migrationCollector) Collect(ch chan<- prometheus.Metric) {
+	version, err := c.source.Version()
+	if err != nil {
+		slog.Warn("failed to read schema version for metrics", slog.Any("error", err))
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(version))
+}
@@ -0,0 +1,116 @@
+package metrics_test
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ecom/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/lib/pq"
+)
+
+type fixedVersion struct {
+	version uint64
+	err     error
+}
+
+func (v fixedVersion) Version() (uint64, error) { return v.version, v.err }
+
+// scrape returns the metrics in the Prometheus text format
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape returned %d", rec.Code)
+	}
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func assertContains(t *testing.T, text string, want ...string) {
+	t.Helper()
+	for _, line := range want {
+		if !strings.Contains(text, line) {
+			t.Errorf("metrics do not contain %q", line)
+		}
+	}
+}
+
+func TestHTTPMetricsUseRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := metrics.New()
+	router := gin.New()
+	router.Use(m.Middleware())
+	router.GET("/products/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	for _, path := range []string{"/products/1", "/products/2", "/nowhere"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	text := scrape(t, m)
+	assertContains(t, text,
+		`ecom_http_request_duration_seconds_count{method="GET",route="/products/:id",status="404"} 2`,
+		`ecom_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`,
+	)
+	if strings.Contains(text, "/products/1") {
+		t.Error("raw paths must not be used as labels")
+	}
+}
+
+func TestBusinessCounters(t *testing.T) {
+	m := metrics.New()
+	m.Business.StockMoved(metrics.StockAdjustment, -3)
+	m.Business.StockMoved(metrics.StockSale, 2)
+	m.Business.StockMoved(metrics.StockSale, 0)
+
+	assertContains(t, scrape(t, m),
+		`ecom_stock_movements_total{type="adjustment"} 1`,
+		`ecom_stock_movements_total{type="sale"} 1`,
+		`ecom_stock_movement_units_total{type="adjustment"} 3`,
+		`ecom_stock_movement_units_total{type="sale"} 2`,
+	)
+
+	// Services built without metrics record nothing
+	var none *metrics.Business
+	none.StockMoved(metrics.StockSale, 1)
+}
+
+func TestDBAndMigrationMetrics(t *testing.T) {
+	// sql.Open does not connect, so the pool statistics are all zero
+	db, err := sql.Open("postgres", "host=localhost dbname=ecom sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	m := metrics.New()
+	m.RegisterDB(db, "ecom")
+	m.RegisterMigrations(fixedVersion{version: 3})
+
+	assertContains(t, scrape(t, m),
+		`go_sql_open_connections{db_name="ecom"} 0`,
+		`go_sql_in_use_connections{db_name="ecom"} 0`,
+		`go_sql_wait_count_total{db_name="ecom"} 0`,
+		`go_sql_wait_duration_seconds_total{db_name="ecom"} 0`,
+		`ecom_schema_migration_version 3`,
+	)
+}
+
+func TestMigrationVersionIsSkippedOnError(t *testing.T) {
+	m := metrics.New()
+	m.RegisterMigrations(fixedVersion{err: errors.New("connection refused")})
+
+	if strings.Contains(scrape(t, m), "ecom_schema_migration_version ") {
+		t.Error("the schema version must be left out when it cannot be read")
+	}
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSPolicy decides which cross-origin browser requests are allowed
+type CORSPolicy struct {
+	// AllowedOrigins are exact origins (https://shop.example.com), subdomain
+	// patterns (https://*.example.com) or * for any; empty allows none
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	// ExposedHeaders are the response headers scripts may read
+	ExposedHeaders []string
+	// AllowCredentials lets browsers send cookies
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight response
+	MaxAge time.Duration
+}
+
+// allowsOrigin reports whether origin matches an allowed origin. Subdomain
+// patterns match any depth (https://a.b.example.com) but not the bare
+// domain. The "null" origin of sandboxed documents never matches.
+func (p *CORSPolicy) allowsOrigin(origin string) bool {
+	if origin == "null" {
+		return false
+	}
+	origin = strings.ToLower(origin)
+	for _, allowed := range p.AllowedOrigins {
+		allowed = strings.ToLower(allowed)
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+		prefix, suffix, ok := strings.Cut(allowed, "://*.")
+		if !ok {
+			continue
+		}
+		prefix += "://"
+		suffix = "." + suffix
+		if len(origin) <= len(prefix)+len(suffix) || !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) {
+			continue
+		}
+		subdomain := origin[len(prefix) : len(origin)-len(suffix)]
+		if strings.Trim(subdomain, "abcdefghijklmnopqrstuvwxyz0123456789-.") == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *CORSPolicy) allowsMethod(method string) bool {
+	for _, allowed := range p.AllowedMethods {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsHeaders reports whether every header in a preflight's
+// Access-Control-Request-Headers list is allowed
+func (p *CORSPolicy) allowsHeaders(requested string) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+		allowed := false
+		for _, h := range p.AllowedHeaders {
+			if strings.EqualFold(h, header) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// CORS applies a CORS policy to cross-origin requests, those with an Origin
+// header. policyFor returns the policy for a route template (e.g.
+// /api/v1/products/:id) and method; nil allows no cross-origin access.
+//
+// A preflight (OPTIONS with Access-Control-Request-Method) is answered with
+// 204 under the policy of the method it asks about, or 403 when the origin,
+// method or a header is not allowed. It needs an OPTIONS route for its path
+// to know the route; other OPTIONS requests pass through. An allowed actual
+// request gets its origin reflected in Access-Control-Allow-Origin. Every
+// response varies by Origin, so caches keep them apart.
+func CORS(policyFor func(method, route string) *CORSPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		requestedMethod := c.GetHeader("Access-Control-Request-Method")
+		if c.Request.Method == http.MethodOptions && requestedMethod != "" {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+
+			policy := policyFor(requestedMethod, c.FullPath())
+			if policy == nil || !policy.allowsOrigin(origin) {
+				Forbidden(c, "Origin "+origin+" is not allowed")
+				c.Abort()
+				return
+			}
+			if !policy.allowsMethod(requestedMethod) || !policy.allowsHeaders(c.GetHeader("Access-Control-Request-Headers")) {
+				Forbidden(c, "The requested method or headers are not allowed")
+				c.Abort()
+				return
+			}
+
+			policy.allowOrigin(header, origin)
+			header.Set("Access-Control-Allow-Methods", strings.Join(policy.AllowedMethods, ", "))
+			if len(policy.AllowedHeaders) > 0 {
+				header.Set("Access-Control-Allow-Headers", strings.Join(policy.AllowedHeaders, ", "))
+			}
+			if policy.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(policy.MaxAge.Seconds())))
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if policy := policyFor(c.Request.Method, c.FullPath()); policy != nil && policy.allowsOrigin(origin) {
+			policy.allowOrigin(header, origin)
+			if len(policy.ExposedHeaders) > 0 {
+				header.Set("Access-Control-Expose-Headers", strings.Join(policy.ExposedHeaders, ", "))
+			}
+		}
+		c.Next()
+	}
+}
+
+// allowOrigin reflects origin, which is valid with credentials where * is not
+func (p *CORSPolicy) allowOrigin(header http.Header, origin string) {
+	header.Set("Access-Control-Allow-Origin", origin)
+	if p.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
@@ -1,81 +1,133 @@
 package middleware
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
+	"runtime/debug"
 	"time"
 
+	"ecom/internal/logging"
+	"ecom/internal/requestid"
+
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// ErrorHandler middleware for handling panics and errors
+// ErrorHandler middleware for handling panics and errors. Handlers report
+// failures with c.Error(err) and return; the last error is mapped to the
+// error envelope unless a response was already written.
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				log.Printf("Panic recovered: %v", err)
+				logging.FromContext(c.Request.Context()).Error("panic recovered",
+					slog.Any("panic", err),
+					slog.String("stack", string(debug.Stack())),
+				)
 				ErrorResponse(c, http.StatusInternalServerError, "Internal Server Error", "An unexpected error occurred")
 			}
 		}()
 		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		AppErrorResponse(c, c.Errors.Last().Err)
 	}
 }
 
-// RequestLogger middleware for logging HTTP requests
-func RequestLogger() gin.HandlerFunc {
+// RequestLogger puts a logger carrying the request's fields (and the trace
+// and span IDs when the request is traced) in the request context, and logs
+// one line per request when it completes. Code further down logs through
+// logging.FromContext and may add fields with logging.With (the customer_id
+// once authenticated); the completion line includes them. 5xx responses are
+// logged at error level and 4xx at warn.
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		startTime := time.Now()
 
-		c.Next()
-
-		duration := time.Since(startTime)
-		statusCode := c.Writer.Status()
-
-		log.Printf(
-			"%s %s %s | Status: %d | Duration: %v | IP: %s",
-			c.Request.Method,
-			c.Request.URL.Path,
-			c.Request.Proto,
-			statusCode,
-			duration,
-			c.ClientIP(),
+		requestLogger := logger.With(
+			slog.String("request_id", requestid.FromContext(c.Request.Context())),
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("client_ip", c.ClientIP()),
 		)
-	}
-}
+		if span := trace.SpanContextFromContext(c.Request.Context()); span.IsValid() {
+			requestLogger = requestLogger.With(
+				slog.String("trace_id", span.TraceID().String()),
+				slog.String("span_id", span.SpanID().String()),
+			)
+		}
+		c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), requestLogger))
 
-// CORSMiddleware for handling CORS headers
-func CORSMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
+		c.Next()
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
-			return
+		statusCode := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case statusCode >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case statusCode >= http.StatusBadRequest:
+			level = slog.LevelWarn
 		}
 
-		c.Next()
+		// c.Request may have been replaced by now; its context has the
+		// fields added downstream
+		ctx := c.Request.Context()
+		logging.FromContext(ctx).LogAttrs(ctx, level, "request completed",
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", statusCode),
+			slog.Float64("latency_ms", float64(time.Since(startTime).Microseconds())/1000),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+		)
 	}
 }
 
-// RequestIDMiddleware adds a unique request ID to context
+// RequestIDMiddleware identifies each request. A valid X-Request-ID header
+// is kept so a caller's ID follows the request through; otherwise a UUIDv7 is
+// generated. The ID is echoed in the X-Request-ID response header and carried
+// in the request context (requestid.FromContext) for error envelopes, log
+// lines and SQL comments.
 func RequestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		requestID := c.GetString("X-Request-ID")
-		if requestID == "" {
-			requestID = time.Now().Format("20060102150405") + "-" + c.ClientIP()
+		requestID := c.GetHeader(requestid.Header)
+		if !requestid.Valid(requestID) {
+			requestID = requestid.New()
 		}
 		c.Set("requestID", requestID)
+		c.Header(requestid.Header, requestID)
+		c.Request = c.Request.WithContext(requestid.WithID(c.Request.Context(), requestID))
 		c.Next()
 	}
 }
 
-// TimeoutMiddleware for request timeouts
+// StatusClientClosedRequest is logged when the client disconnects before the
+// response is written (the nginx convention; it never reaches the client)
+const StatusClientClosedRequest = 499
+
+// TimeoutMiddleware bounds each request with a deadline on the request
+// context. Repositories run their queries with that context, so an expired
+// request cancels its SQL; if the handler has not written a response by then,
+// a 504 is returned in the standard envelope. A query that exceeds its own
+// (shorter) deadline is reported by the handler as a 503 instead. A timeout of
+// 0 disables the middleware.
 func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// This is handled at server level, but you can add custom timeout logic here if needed
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
 		c.Next()
+
+		if ctx.Err() == context.DeadlineExceeded && !c.Writer.Written() {
+			GatewayTimeout(c, "The request took too long to process")
+			c.Abort()
+		}
 	}
 }
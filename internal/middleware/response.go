@@ -1,10 +1,17 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
 	"time"
 
+	"ecom/internal/apperrors"
 	"ecom/internal/dto"
+	"ecom/internal/logging"
+	"ecom/internal/requestid"
+	"ecom/internal/validation"
 	"github.com/gin-gonic/gin"
 )
 
@@ -21,12 +28,25 @@ type ApiResponse struct {
 	Timestamp string      `json:"timestamp" example:"2026-01-27T10:30:00Z"`
 }
 
+// ErrorApiResponse is the standard error response structure for Swagger
+// @Description Error response with a stable machine-readable code
+type ErrorApiResponse struct {
+	Success   bool             `json:"success" example:"false"`
+	Error     string           `json:"error" example:"Conflict"`
+	Code      string           `json:"code" example:"DUPLICATE_VALUE"`
+	Field     string           `json:"field,omitempty" example:"sku"`
+	Details   []dto.FieldError `json:"details,omitempty"`
+	Message   string           `json:"message" example:"sku already exists"`
+	RequestID string           `json:"request_id,omitempty" example:"01966b3e-8f2a-7c4d-9e1f-2a3b4c5d6e7f"`
+	Timestamp string           `json:"timestamp" example:"2026-01-27T10:30:00Z"`
+}
+
 // ListApiResponse is the standard list response structure for Swagger
 // @Description Paginated list response wrapper
 type ListApiResponse struct {
 	Success    bool        `json:"success" example:"true"`
 	Data       interface{} `json:"data"`
-	Pagination *Pagination `json:"pagination"`
+	Pagination Pagination  `json:"pagination"`
 	Message    string      `json:"message" example:"Retrieved successfully"`
 	Timestamp  string      `json:"timestamp" example:"2026-01-27T10:30:00Z"`
 }
@@ -70,15 +90,110 @@ func ListResponse(c *gin.Context, statusCode int, data interface{}, page, limit,
 
 // ErrorResponse returns a standardized error response
 func ErrorResponse(c *gin.Context, statusCode int, error, message string) {
+	writeError(c, statusCode, error, defaultErrorCode(statusCode), "", message)
+}
+
+// AppErrorResponse maps err to an error response. Domain errors from
+// apperrors keep their status, code and field; anything else is logged and
+// returned as a generic 500 so internal details never reach the client.
+func AppErrorResponse(c *gin.Context, err error) {
+	// The client went away; there is nobody left to read an error envelope
+	if errors.Is(err, context.Canceled) && c.Request.Context().Err() == context.Canceled {
+		c.AbortWithStatus(StatusClientClosedRequest)
+		return
+	}
+
+	appErr, ok := apperrors.As(err)
+	if !ok {
+		logging.FromContext(c.Request.Context()).Error("unhandled error", slog.Any("error", err))
+		InternalError(c, "An unexpected error occurred")
+		return
+	}
+
+	statusCode := statusForKind(appErr.Kind)
+	message := appErr.Message
+	if statusCode >= http.StatusInternalServerError {
+		logging.FromContext(c.Request.Context()).Error("request failed", slog.Any("error", err))
+		if appErr.Kind == apperrors.KindInternal {
+			message = "An unexpected error occurred"
+		}
+	}
+
+	code := appErr.Code
+	if code == "" {
+		code = defaultErrorCode(statusCode)
+	}
+	writeError(c, statusCode, http.StatusText(statusCode), code, appErr.Field, message)
+}
+
+// ValidationFailed returns 400 with one detail per failed field for a
+// request binding error
+func ValidationFailed(c *gin.Context, err error) {
+	response := dto.ErrorResponseData{
+		Success:   false,
+		Error:     http.StatusText(http.StatusBadRequest),
+		Code:      apperrors.CodeValidation,
+		Details:   validation.Details(err),
+		Message:   "Validation failed",
+		RequestID: requestid.FromContext(c.Request.Context()),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	c.JSON(http.StatusBadRequest, response)
+}
+
+func writeError(c *gin.Context, statusCode int, error, code, field, message string) {
 	response := dto.ErrorResponseData{
 		Success:   false,
 		Error:     error,
+		Code:      code,
+		Field:     field,
 		Message:   message,
+		RequestID: requestid.FromContext(c.Request.Context()),
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 	}
 	c.JSON(statusCode, response)
 }
 
+func statusForKind(kind apperrors.Kind) int {
+	switch kind {
+	case apperrors.KindNotFound:
+		return http.StatusNotFound
+	case apperrors.KindConflict:
+		return http.StatusConflict
+	case apperrors.KindValidation:
+		return http.StatusBadRequest
+	case apperrors.KindUnauthorized:
+		return http.StatusUnauthorized
+	case apperrors.KindForbidden:
+		return http.StatusForbidden
+	case apperrors.KindUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func defaultErrorCode(statusCode int) string {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return apperrors.CodeValidation
+	case http.StatusUnauthorized:
+		return apperrors.CodeUnauthorized
+	case http.StatusForbidden:
+		return apperrors.CodeForbidden
+	case http.StatusNotFound:
+		return apperrors.CodeNotFound
+	case http.StatusConflict:
+		return apperrors.CodeConflict
+	case http.StatusServiceUnavailable:
+		return apperrors.CodeUnavailable
+	case http.StatusGatewayTimeout:
+		return apperrors.CodeRequestTimeout
+	default:
+		return apperrors.CodeInternal
+	}
+}
+
 // ===========================
 // Convenience Methods
 // ===========================
@@ -127,3 +242,20 @@ func InternalError(c *gin.Context, message string) {
 func ServiceUnavailable(c *gin.Context, message string) {
 	ErrorResponse(c, http.StatusServiceUnavailable, "Service Unavailable", message)
 }
+
+// ServiceUnavailableWithData returns 503 in the success envelope with
+// success false, for probes whose body says which dependency failed
+func ServiceUnavailableWithData(c *gin.Context, data interface{}, message string) {
+	response := dto.SuccessResponse{
+		Success:   false,
+		Data:      data,
+		Message:   message,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	c.JSON(http.StatusServiceUnavailable, response)
+}
+
+// GatewayTimeout returns 504 Gateway Timeout response
+func GatewayTimeout(c *gin.Context, message string) {
+	ErrorResponse(c, http.StatusGatewayTimeout, "Gateway Timeout", message)
+}
@@ -12,21 +12,89 @@ type User struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
-// Product represents a product in the e-commerce system
-type Product struct {
-	ID          int       `json:"id"`
+// Category represents a row of the categories table
+type Category struct {
+	ID          int64     `json:"id"`
 	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
 	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	Stock       int       `json:"stock"`
+	ParentID    *int64    `json:"parent_id"`
+	ImageURL    *string   `json:"image_url"`
+	IsActive    bool      `json:"is_active"`
+	SortOrder   int       `json:"sort_order"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-// HealthCheck represents the health check response
-type HealthCheck struct {
-	Status    string `json:"status"`
-	Message   string `json:"message"`
-	Timestamp string `json:"timestamp"`
+// Product represents a row of the products table
+type Product struct {
+	ID                int64     `json:"id"`
+	SKU               string    `json:"sku"`
+	Name              string    `json:"name"`
+	Slug              string    `json:"slug"`
+	Description       string    `json:"description"`
+	ShortDescription  string    `json:"short_description"`
+	CategoryID        int64     `json:"category_id"`
+	Status            string    `json:"status"`
+	Price             float64   `json:"price"`
+	CompareAtPrice    *float64  `json:"compare_at_price"`
+	CostPrice         *float64  `json:"cost_price"`
+	StockQuantity     int       `json:"stock_quantity"`
+	LowStockThreshold int       `json:"low_stock_threshold"`
+	WeightKg          *float64  `json:"weight_kg"`
+	DimensionsCm      *string   `json:"dimensions_cm"`
+	Barcode           *string   `json:"barcode"`
+	Manufacturer      *string   `json:"manufacturer"`
+	Brand             *string   `json:"brand"`
+	RatingAverage     float64   `json:"rating_average"`
+	RatingCount       int       `json:"rating_count"`
+	ViewCount         int       `json:"view_count"`
+	IsFeatured        bool      `json:"is_featured"`
+	MetaTitle         *string   `json:"meta_title"`
+	MetaDescription   *string   `json:"meta_description"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// Customer represents a row of the customers table
+type Customer struct {
+	ID              int64      `json:"id"`
+	Email           string     `json:"email"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	Phone           *string    `json:"phone"`
+	DateOfBirth     *time.Time `json:"date_of_birth"`
+	IsActive        bool       `json:"is_active"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	LastLoginAt     *time.Time `json:"last_login_at"`
+	// PasswordHash is empty for customers who cannot log in
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// StaffUser represents a row of the staff_users table
+type StaffUser struct {
+	ID           int64      `json:"id"`
+	Email        string     `json:"email"`
+	Name         string     `json:"name"`
+	PasswordHash string     `json:"-"`
+	IsActive     bool       `json:"is_active"`
+	LastLoginAt  *time.Time `json:"last_login_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// RefreshToken represents a row of the refresh_tokens table. Exactly one of
+// CustomerID and StaffUserID is set.
+type RefreshToken struct {
+	ID          int64
+	TokenHash   []byte
+	FamilyID    string
+	CustomerID  *int64
+	StaffUserID *int64
+	ExpiresAt   time.Time
+	RotatedAt   *time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+}
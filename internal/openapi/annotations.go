@@ -0,0 +1,399 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// sourceDirs hold the annotated code, relative to the module root: the
+// general API info in cmd/server, the handlers and the documented types
+var sourceDirs = []string{
+	"cmd/server",
+	"internal/handlers",
+	"internal/middleware",
+	"internal/routes",
+}
+
+// apiInfo is the general API information annotated on cmd/server
+type apiInfo struct {
+	title       string
+	version     string
+	description string
+	host        string
+	basePath    string
+	schemes     []string
+	licenseName string
+	licenseURL  string
+	tags        []tagDoc
+	// bearerSchemes are the @securityDefinitions.bearer schemes, in order
+	bearerSchemes []bearerScheme
+}
+
+// bearerScheme is one @securityDefinitions.bearer: name [format]
+type bearerScheme struct {
+	name   string
+	format string
+}
+
+type tagDoc struct {
+	name        string
+	description string
+}
+
+// operationDoc is the swag-style annotation of one handler func
+type operationDoc struct {
+	pos         string
+	id          string
+	summary     string
+	description string
+	tags        []string
+	accept      []string
+	produce     []string
+	params      []paramDoc
+	responses   []responseDoc
+	routes      []routeDoc
+	deprecated  bool
+	// security names the schemes of @Security, any of which is accepted
+	security []string
+}
+
+// paramDoc is one @Param: name in type required "description" attrs...
+type paramDoc struct {
+	name        string
+	in          string
+	typ         string
+	required    bool
+	description string
+	attrs       map[string]string
+}
+
+// responseDoc is one @Success or @Failure: status {kind} type "description"
+type responseDoc struct {
+	status      string
+	kind        string
+	typ         string
+	description string
+}
+
+// routeDoc is one @Router: path [method]
+type routeDoc struct {
+	path   string
+	method string
+}
+
+// sources are the annotations found in sourceDirs
+type sources struct {
+	info apiInfo
+	// ops are keyed by func: "handlers.CategoryHandler.CreateCategory" or
+	// "routes.healthCheck"
+	ops map[string]*operationDoc
+	// typeDocs are @Description annotations of types, keyed "middleware.ApiResponse"
+	typeDocs map[string]string
+}
+
+func parseSources(root string) (*sources, error) {
+	src := &sources{
+		ops:      make(map[string]*operationDoc),
+		typeDocs: make(map[string]string),
+	}
+
+	fset := token.NewFileSet()
+	notTest := func(fi fs.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }
+	for _, dir := range sourceDirs {
+		pkgs, err := parser.ParseDir(fset, filepath.Join(root, dir), notTest, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", dir, err)
+		}
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Files {
+				if err := src.addFile(fset, pkg.Name, file); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return src, nil
+}
+
+func (s *sources) addFile(fset *token.FileSet, pkgName string, file *ast.File) error {
+	if pkgName == "main" {
+		for _, group := range file.Comments {
+			s.info.add(annotations(group))
+		}
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			lines := annotations(decl.Doc)
+			if len(lines) == 0 {
+				continue
+			}
+			pos := fset.Position(decl.Pos()).String()
+			op, err := parseOperation(lines, pos)
+			if err != nil {
+				return err
+			}
+			s.ops[funcKey(pkgName, decl)] = op
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				doc := typeSpec.Doc
+				if doc == nil && len(decl.Specs) == 1 {
+					doc = decl.Doc
+				}
+				for _, line := range annotations(doc) {
+					if line.name == "@description" {
+						s.typeDocs[pkgName+"."+typeSpec.Name.Name] = line.value
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// funcKey identifies a func the way handlerKey identifies a route handler
+func funcKey(pkgName string, decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return pkgName + "." + decl.Name.Name
+	}
+	recv := decl.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return pkgName + "." + ident.Name + "." + decl.Name.Name
+	}
+	return pkgName + "." + decl.Name.Name
+}
+
+// handlerKey turns the name gin reports for a route handler, e.g.
+// "ecom/internal/handlers.(*CategoryHandler).CreateCategory-fm", into a
+// funcKey: "handlers.CategoryHandler.CreateCategory"
+func handlerKey(name string) string {
+	name = strings.TrimSuffix(name, "-fm")
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+	return name
+}
+
+// annotation is one "@name value" comment line
+type annotation struct {
+	name  string
+	value string
+}
+
+func annotations(group *ast.CommentGroup) []annotation {
+	if group == nil {
+		return nil
+	}
+	var lines []annotation
+	for _, line := range strings.Split(group.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+		name, value, _ := strings.Cut(line, " ")
+		lines = append(lines, annotation{name: strings.ToLower(name), value: strings.TrimSpace(value)})
+	}
+	return lines
+}
+
+func (info *apiInfo) add(lines []annotation) {
+	for _, line := range lines {
+		switch line.name {
+		case "@title":
+			info.title = line.value
+		case "@version":
+			info.version = line.value
+		case "@description":
+			info.description = joinText(info.description, line.value)
+		case "@host":
+			info.host = line.value
+		case "@basepath":
+			info.basePath = line.value
+		case "@schemes":
+			info.schemes = strings.Fields(line.value)
+		case "@license.name":
+			info.licenseName = line.value
+		case "@license.url":
+			info.licenseURL = line.value
+		case "@tag.name":
+			info.tags = append(info.tags, tagDoc{name: line.value})
+		case "@tag.description":
+			if len(info.tags) > 0 {
+				info.tags[len(info.tags)-1].description = line.value
+			}
+		case "@securitydefinitions.bearer":
+			name, format, _ := strings.Cut(line.value, " ")
+			info.bearerSchemes = append(info.bearerSchemes, bearerScheme{name: name, format: strings.TrimSpace(format)})
+		}
+	}
+}
+
+func parseOperation(lines []annotation, pos string) (*operationDoc, error) {
+	op := &operationDoc{pos: pos}
+	for _, line := range lines {
+		var err error
+		switch line.name {
+		case "@id":
+			op.id = line.value
+		case "@summary":
+			op.summary = line.value
+		case "@description":
+			op.description = joinText(op.description, line.value)
+		case "@tags":
+			op.tags = append(op.tags, splitList(line.value)...)
+		case "@accept":
+			op.accept = append(op.accept, splitList(line.value)...)
+		case "@produce":
+			op.produce = append(op.produce, splitList(line.value)...)
+		case "@deprecated":
+			op.deprecated = true
+		case "@security":
+			op.security = append(op.security, splitList(line.value)...)
+		case "@param":
+			var param paramDoc
+			param, err = parseParam(line.value)
+			op.params = append(op.params, param)
+		case "@success", "@failure":
+			var response responseDoc
+			response, err = parseResponse(line.value)
+			op.responses = append(op.responses, response)
+		case "@router":
+			var route routeDoc
+			route, err = parseRouter(line.value)
+			op.routes = append(op.routes, route)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s %s: %w", pos, line.name, line.value, err)
+		}
+	}
+	return op, nil
+}
+
+func parseParam(value string) (paramDoc, error) {
+	fields := tokenize(value)
+	if len(fields) < 4 {
+		return paramDoc{}, fmt.Errorf("want name, in, type and required")
+	}
+	param := paramDoc{
+		name:     fields[0],
+		in:       fields[1],
+		typ:      fields[2],
+		required: fields[3] == "true",
+		attrs:    make(map[string]string),
+	}
+	switch param.in {
+	case "path", "query", "header", "body":
+	default:
+		return paramDoc{}, fmt.Errorf("unsupported parameter location %q", param.in)
+	}
+	for _, field := range fields[4:] {
+		if text, ok := unquote(field); ok {
+			param.description = text
+			continue
+		}
+		// Attributes look like default(10) or enums(a,b)
+		name, arg, ok := strings.Cut(field, "(")
+		if !ok || !strings.HasSuffix(arg, ")") {
+			return paramDoc{}, fmt.Errorf("malformed attribute %q", field)
+		}
+		param.attrs[strings.ToLower(name)] = strings.TrimSuffix(arg, ")")
+	}
+	return param, nil
+}
+
+func parseResponse(value string) (responseDoc, error) {
+	fields := tokenize(value)
+	if len(fields) == 0 {
+		return responseDoc{}, fmt.Errorf("want a status code")
+	}
+	response := responseDoc{status: fields[0]}
+	rest := fields[1:]
+	if len(rest) > 0 && strings.HasPrefix(rest[0], "{") {
+		response.kind = strings.Trim(rest[0], "{}")
+		if len(rest) < 2 {
+			return responseDoc{}, fmt.Errorf("want a type after {%s}", response.kind)
+		}
+		response.typ = rest[1]
+		rest = rest[2:]
+	}
+	if len(rest) > 0 {
+		text, ok := unquote(rest[0])
+		if !ok {
+			return responseDoc{}, fmt.Errorf("unexpected %q", rest[0])
+		}
+		response.description = text
+	}
+	return response, nil
+}
+
+func parseRouter(value string) (routeDoc, error) {
+	path, method, ok := strings.Cut(value, " ")
+	method = strings.TrimSpace(method)
+	if !ok || !strings.HasPrefix(method, "[") || !strings.HasSuffix(method, "]") {
+		return routeDoc{}, fmt.Errorf("want path [method]")
+	}
+	return routeDoc{path: path, method: strings.ToUpper(strings.Trim(method, "[]"))}, nil
+}
+
+// tokenize splits on spaces, keeping "quoted text" together
+func tokenize(value string) []string {
+	var (
+		fields  []string
+		current strings.Builder
+		quoted  bool
+	)
+	for _, r := range value {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case r == ' ' && !quoted:
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
+
+func unquote(field string) (string, bool) {
+	if len(field) < 2 || field[0] != '"' || field[len(field)-1] != '"' {
+		return "", false
+	}
+	return field[1 : len(field)-1], true
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func joinText(text, line string) string {
+	if text == "" {
+		return line
+	}
+	return text + "\n" + line
+}
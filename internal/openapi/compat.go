@@ -0,0 +1,112 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// downgrade rewrites the OpenAPI 3.1 constructs the generator emits to their
+// 3.0 equivalents, because kin-openapi only validates 3.0 schemas:
+//
+//   - type: [T, "null"] becomes type: T with nullable: true
+//   - anyOf: [S, {type: "null"}] becomes anyOf: [S] with nullable: true
+//   - numeric exclusiveMinimum/exclusiveMaximum become minimum/maximum with
+//     the boolean flag
+//   - a schema's examples list becomes its example
+//   - the empty schema {}, which allows any value, also allows null
+//
+// Other documents are returned unchanged.
+func downgrade(data []byte) ([]byte, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+	version, _ := doc["openapi"].(string)
+	if !strings.HasPrefix(version, "3.1") {
+		return data, nil
+	}
+
+	doc["openapi"] = "3.0.3"
+	downgradeValue(doc)
+	return yaml.Marshal(doc)
+}
+
+func downgradeValue(value any) {
+	switch value := value.(type) {
+	case map[string]any:
+		downgradeSchema(value)
+		for key, child := range value {
+			switch key {
+			case "schema", "items", "additionalProperties":
+				allowNull(child)
+			case "properties":
+				if props, ok := child.(map[string]any); ok {
+					for _, prop := range props {
+						allowNull(prop)
+					}
+				}
+			}
+			downgradeValue(child)
+		}
+	case []any:
+		for _, child := range value {
+			downgradeValue(child)
+		}
+	}
+}
+
+func downgradeSchema(s map[string]any) {
+	// Only schemas have a list of types or a list of examples; media types
+	// and parameters keep examples in a map
+	if types, ok := s["type"].([]any); ok {
+		var rest []any
+		for _, t := range types {
+			if t == "null" {
+				s["nullable"] = true
+				continue
+			}
+			rest = append(rest, t)
+		}
+		if len(rest) == 1 {
+			s["type"] = rest[0]
+		} else {
+			s["type"] = rest
+		}
+	}
+
+	if anyOf, ok := s["anyOf"].([]any); ok {
+		var rest []any
+		for _, option := range anyOf {
+			if option, ok := option.(map[string]any); ok && len(option) == 1 && option["type"] == "null" {
+				s["nullable"] = true
+				continue
+			}
+			rest = append(rest, option)
+		}
+		s["anyOf"] = rest
+	}
+
+	for keyword, bound := range map[string]string{"exclusiveMinimum": "minimum", "exclusiveMaximum": "maximum"} {
+		switch value := s[keyword].(type) {
+		case int, float64:
+			s[bound] = value
+			s[keyword] = true
+		}
+	}
+
+	if examples, ok := s["examples"].([]any); ok {
+		if len(examples) > 0 {
+			s["example"] = examples[0]
+		}
+		delete(s, "examples")
+	}
+}
+
+// allowNull marks an empty schema nullable: in 3.0 {} does not match null
+func allowNull(s any) {
+	if s, ok := s.(map[string]any); ok && len(s) == 0 {
+		s["nullable"] = true
+	}
+}
@@ -0,0 +1,516 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// header marks the spec as generated
+const header = "# Code generated by go run ./cmd/openapi; DO NOT EDIT.\n"
+
+// document is the subset of an OpenAPI 3.1 document the generator emits
+type document struct {
+	OpenAPI    string              `yaml:"openapi"`
+	Info       info                `yaml:"info"`
+	Servers    []server            `yaml:"servers,omitempty"`
+	Tags       []tag               `yaml:"tags,omitempty"`
+	Paths      map[string]pathItem `yaml:"paths"`
+	Components components          `yaml:"components"`
+}
+
+type info struct {
+	Title       string   `yaml:"title"`
+	Version     string   `yaml:"version"`
+	Description string   `yaml:"description,omitempty"`
+	License     *license `yaml:"license,omitempty"`
+}
+
+type license struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url,omitempty"`
+}
+
+type server struct {
+	URL string `yaml:"url"`
+}
+
+type tag struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// pathItem maps lower-case HTTP methods to operations
+type pathItem map[string]*operation
+
+type operation struct {
+	Tags        []string              `yaml:"tags,omitempty"`
+	Summary     string                `yaml:"summary,omitempty"`
+	Description string                `yaml:"description,omitempty"`
+	OperationID string                `yaml:"operationId"`
+	Parameters  []parameter           `yaml:"parameters,omitempty"`
+	RequestBody *requestBody          `yaml:"requestBody,omitempty"`
+	Responses   map[string]response   `yaml:"responses"`
+	Deprecated  bool                  `yaml:"deprecated,omitempty"`
+	Security    []map[string][]string `yaml:"security,omitempty"`
+}
+
+type parameter struct {
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description,omitempty"`
+	Required    bool    `yaml:"required,omitempty"`
+	Schema      *schema `yaml:"schema"`
+}
+
+type requestBody struct {
+	Description string               `yaml:"description,omitempty"`
+	Required    bool                 `yaml:"required,omitempty"`
+	Content     map[string]mediaType `yaml:"content"`
+}
+
+type response struct {
+	Description string               `yaml:"description"`
+	Content     map[string]mediaType `yaml:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *schema `yaml:"schema"`
+}
+
+type components struct {
+	Schemas         map[string]*schema        `yaml:"schemas"`
+	SecuritySchemes map[string]securityScheme `yaml:"securitySchemes,omitempty"`
+}
+
+type securityScheme struct {
+	Type         string `yaml:"type"`
+	Scheme       string `yaml:"scheme"`
+	BearerFormat string `yaml:"bearerFormat,omitempty"`
+}
+
+// Generate builds an OpenAPI 3.1 document, as YAML, for the routes
+// registered on a router. Operations come from the swag-style annotations
+// of each route's handler in the sources under root, and schemas from the
+// Go types those annotations name, with binding tags as constraints.
+//
+// Routes whose path is in skip are left out. Every other route must be
+// annotated, and every @Router annotation must match a registered route.
+func Generate(routes gin.RoutesInfo, root string, skip ...string) ([]byte, error) {
+	src, err := parseSources(root)
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{
+		src:     src,
+		schemas: newSchemaBuilder(src.typeDocs),
+		opIDs:   make(map[string]int),
+	}
+
+	doc := &document{
+		OpenAPI: "3.1.0",
+		Info: info{
+			Title:       src.info.title,
+			Version:     src.info.version,
+			Description: src.info.description,
+		},
+		Paths: make(map[string]pathItem),
+	}
+	if src.info.licenseName != "" {
+		doc.Info.License = &license{Name: src.info.licenseName, URL: src.info.licenseURL}
+	}
+	for _, scheme := range src.info.schemes {
+		url := scheme + "://" + src.info.host + strings.TrimSuffix(src.info.basePath, "/")
+		doc.Servers = append(doc.Servers, server{URL: url})
+	}
+	for _, t := range src.info.tags {
+		doc.Tags = append(doc.Tags, tag{Name: t.name, Description: t.description})
+	}
+	for _, scheme := range src.info.bearerSchemes {
+		if doc.Components.SecuritySchemes == nil {
+			doc.Components.SecuritySchemes = make(map[string]securityScheme)
+		}
+		doc.Components.SecuritySchemes[scheme.name] = securityScheme{Type: "http", Scheme: "bearer", BearerFormat: scheme.format}
+	}
+
+	skipped := make(map[string]bool, len(skip))
+	for _, path := range skip {
+		skipped[path] = true
+	}
+
+	// Sorted, so operation IDs of handlers serving several routes are stable
+	routes = append(gin.RoutesInfo(nil), routes...)
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	var errs []error
+	documented := make(map[*operationDoc]map[routeDoc]bool)
+	for _, route := range routes {
+		if skipped[route.Path] {
+			continue
+		}
+		path := ginParam.ReplaceAllString(route.Path, "{$1}")
+		key := handlerKey(route.Handler)
+		opDoc, ok := src.ops[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s %s: handler %s has no annotations", route.Method, path, key))
+			continue
+		}
+
+		at := routeDoc{path: path, method: route.Method}
+		if documented[opDoc] == nil {
+			documented[opDoc] = make(map[routeDoc]bool)
+		}
+		documented[opDoc][at] = true
+
+		op, err := g.operation(opDoc, key, at, doc.Components.SecuritySchemes)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s (%s): %w", route.Method, path, opDoc.pos, err))
+			continue
+		}
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(pathItem)
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = op
+	}
+
+	// @Router annotations that no longer match a registered route
+	for key, opDoc := range src.ops {
+		if len(opDoc.routes) == 0 {
+			continue
+		}
+		for _, at := range opDoc.routes {
+			if !documented[opDoc][at] && !skipped[at.path] {
+				errs = append(errs, fmt.Errorf("%s: %s documents %s %s, which is not registered", opDoc.pos, key, at.method, at.path))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+		return nil, errors.Join(errs...)
+	}
+
+	doc.Components.Schemas = g.schemas.components
+
+	var out bytes.Buffer
+	out.WriteString(header)
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode spec: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode spec: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+type generator struct {
+	src     *sources
+	schemas *schemaBuilder
+	// opIDs counts the operations derived from each handler
+	opIDs map[string]int
+}
+
+// pathParam matches the {name} segments of an OpenAPI path
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+func (g *generator) operation(opDoc *operationDoc, key string, at routeDoc, schemes map[string]securityScheme) (*operation, error) {
+	if !containsRoute(opDoc.routes, at) {
+		return nil, fmt.Errorf("%s has no @Router %s [%s]", key, at.path, strings.ToLower(at.method))
+	}
+
+	op := &operation{
+		Tags:        opDoc.tags,
+		Summary:     opDoc.summary,
+		Description: opDoc.description,
+		OperationID: g.operationID(opDoc, key),
+		Responses:   make(map[string]response),
+		Deprecated:  opDoc.deprecated,
+	}
+
+	inPath := make(map[string]bool)
+	for _, param := range opDoc.params {
+		if param.in == "body" {
+			body, err := g.requestBody(opDoc, param)
+			if err != nil {
+				return nil, err
+			}
+			op.RequestBody = body
+			continue
+		}
+		p, err := parameterFor(param)
+		if err != nil {
+			return nil, fmt.Errorf("@Param %s: %w", param.name, err)
+		}
+		if param.in == "path" {
+			inPath[param.name] = true
+		}
+		op.Parameters = append(op.Parameters, p)
+	}
+	for _, match := range pathParam.FindAllStringSubmatch(at.path, -1) {
+		if !inPath[match[1]] {
+			return nil, fmt.Errorf("path parameter %s is not documented", match[1])
+		}
+	}
+
+	for _, r := range opDoc.responses {
+		resp, err := g.response(opDoc, r)
+		if err != nil {
+			return nil, fmt.Errorf("response %s: %w", r.status, err)
+		}
+		op.Responses[r.status] = resp
+	}
+	if len(op.Responses) == 0 {
+		return nil, fmt.Errorf("no @Success or @Failure responses")
+	}
+
+	for _, name := range opDoc.security {
+		if _, ok := schemes[name]; !ok {
+			return nil, fmt.Errorf("@Security %s: no @securityDefinitions.bearer %s in cmd/server", name, name)
+		}
+		op.Security = append(op.Security, map[string][]string{name: {}})
+	}
+	return op, nil
+}
+
+// operationID is @ID, or the handler name; handlers serving several routes
+// get a numeric suffix from the second route on
+func (g *generator) operationID(opDoc *operationDoc, key string) string {
+	id := opDoc.id
+	if id == "" {
+		name := key[strings.LastIndex(key, ".")+1:]
+		id = strings.ToLower(name[:1]) + name[1:]
+	}
+	g.opIDs[key]++
+	if n := g.opIDs[key]; n > 1 {
+		id += strconv.Itoa(n)
+	}
+	return id
+}
+
+func (g *generator) requestBody(opDoc *operationDoc, param paramDoc) (*requestBody, error) {
+	s, err := g.typeExpr(param.typ, inRequest)
+	if err != nil {
+		return nil, fmt.Errorf("@Param %s: %w", param.name, err)
+	}
+	return &requestBody{
+		Description: param.description,
+		Required:    param.required,
+		Content:     content(opDoc.accept, s),
+	}, nil
+}
+
+func (g *generator) response(opDoc *operationDoc, r responseDoc) (response, error) {
+	resp := response{Description: r.description}
+	if resp.Description == "" {
+		resp.Description = statusText(r.status)
+	}
+	if r.typ == "" {
+		return resp, nil
+	}
+
+	s, err := g.typeExpr(r.typ, inResponse)
+	if err != nil {
+		return response{}, err
+	}
+	switch r.kind {
+	case "object":
+	case "array":
+		s = &schema{Type: schemaType{"array"}, Items: s}
+	default:
+		return response{}, fmt.Errorf("unsupported kind {%s}", r.kind)
+	}
+	resp.Content = content(opDoc.produce, s)
+	return resp, nil
+}
+
+// typeExpr resolves a type named in an annotation: a model, a primitive,
+// []T, map[string]T or Model{field=T,...}, which overrides fields of Model
+func (g *generator) typeExpr(expr string, use usage) (*schema, error) {
+	if elem, ok := strings.CutPrefix(expr, "[]"); ok {
+		items, err := g.typeExpr(elem, use)
+		if err != nil {
+			return nil, err
+		}
+		return &schema{Type: schemaType{"array"}, Items: items}, nil
+	}
+	if elem, ok := strings.CutPrefix(expr, "map[string]"); ok {
+		values, err := g.typeExpr(elem, use)
+		if err != nil {
+			return nil, err
+		}
+		return &schema{Type: schemaType{"object"}, AdditionalProperties: values}, nil
+	}
+
+	name, overrides, composed := strings.Cut(expr, "{")
+	if s := primitive(name); s != nil && !composed {
+		return s, nil
+	}
+	t, ok := models[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %s (add it to models in internal/openapi/models.go)", name)
+	}
+	base, err := g.schemas.schemaFor(t, use)
+	if err != nil {
+		return nil, err
+	}
+	if !composed {
+		return base, nil
+	}
+
+	override := &schema{Type: schemaType{"object"}}
+	for _, field := range splitTopLevel(strings.TrimSuffix(overrides, "}")) {
+		fieldName, fieldType, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed field override %q in %s", field, expr)
+		}
+		s, err := g.typeExpr(fieldType, use)
+		if err != nil {
+			return nil, err
+		}
+		override.Properties = append(override.Properties, property{name: fieldName, schema: s})
+	}
+	return &schema{AllOf: []*schema{base, override}}, nil
+}
+
+// splitTopLevel splits on commas outside of braces
+func splitTopLevel(list string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i, r := range list {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, list[start:])
+}
+
+// primitive returns the schema of a swag primitive type name, or nil
+func primitive(name string) *schema {
+	switch name {
+	case "int", "integer", "int32":
+		return &schema{Type: schemaType{"integer"}}
+	case "int64":
+		return &schema{Type: schemaType{"integer"}, Format: "int64"}
+	case "number", "float", "float64":
+		return &schema{Type: schemaType{"number"}}
+	case "bool", "boolean":
+		return &schema{Type: schemaType{"boolean"}}
+	case "string":
+		return &schema{Type: schemaType{"string"}}
+	case "object":
+		return &schema{Type: schemaType{"object"}}
+	}
+	return nil
+}
+
+func parameterFor(param paramDoc) (parameter, error) {
+	s := primitive(param.typ)
+	if s == nil {
+		return parameter{}, fmt.Errorf("unsupported type %s", param.typ)
+	}
+	for name, value := range param.attrs {
+		var err error
+		switch name {
+		case "default":
+			s.Default, err = typedValue(s.kind(), value)
+		case "minimum":
+			err = s.lowerBound(value, false)
+		case "maximum":
+			err = s.upperBound(value, false)
+		case "enums":
+			for _, item := range splitList(value) {
+				var typed any
+				if typed, err = typedValue(s.kind(), item); err != nil {
+					break
+				}
+				s.Enum = append(s.Enum, typed)
+			}
+		default:
+			err = fmt.Errorf("unsupported attribute %s", name)
+		}
+		if err != nil {
+			return parameter{}, fmt.Errorf("%s(%s): %w", name, value, err)
+		}
+	}
+	return parameter{
+		Name:        param.name,
+		In:          param.in,
+		Description: param.description,
+		Required:    param.required || param.in == "path",
+		Schema:      s,
+	}, nil
+}
+
+// content maps the @Accept/@Produce shorthands to media types; JSON when
+// none are given
+func content(types []string, s *schema) map[string]mediaType {
+	if len(types) == 0 {
+		types = []string{"json"}
+	}
+	media := make(map[string]mediaType, len(types))
+	for _, t := range types {
+		media[mimeType(t)] = mediaType{Schema: s}
+	}
+	return media
+}
+
+func mimeType(shorthand string) string {
+	switch shorthand {
+	case "json":
+		return "application/json"
+	case "xml":
+		return "application/xml"
+	case "plain":
+		return "text/plain"
+	case "html":
+		return "text/html"
+	case "yaml":
+		return "application/yaml"
+	}
+	return shorthand
+}
+
+func statusText(status string) string {
+	if status == "default" {
+		return "Unexpected error"
+	}
+	code, err := strconv.Atoi(status)
+	if err != nil || http.StatusText(code) == "" {
+		return status
+	}
+	return http.StatusText(code)
+}
+
+func containsRoute(routes []routeDoc, at routeDoc) bool {
+	for _, route := range routes {
+		if route == at {
+			return true
+		}
+	}
+	return false
+}
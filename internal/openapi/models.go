@@ -0,0 +1,42 @@
+package openapi
+
+import (
+	"reflect"
+
+	"ecom/internal/dto"
+	"ecom/internal/middleware"
+)
+
+// models are the types @Param and @Success/@Failure annotations may name.
+// Types they contain are found by reflection and need not be listed.
+var models = index(
+	middleware.ApiResponse{},
+	middleware.ListApiResponse{},
+	middleware.ErrorApiResponse{},
+
+	dto.CreateCategoryRequest{},
+	dto.UpdateCategoryRequest{},
+	dto.CategoryResponse{},
+
+	dto.CreateProductRequest{},
+	dto.UpdateProductRequest{},
+	dto.ProductResponse{},
+
+	dto.RegisterRequest{},
+	dto.LoginRequest{},
+	dto.RefreshTokenRequest{},
+	dto.TokenResponse{},
+	dto.PrincipalResponse{},
+
+	dto.LivenessResponse{},
+	dto.ReadinessResponse{},
+)
+
+func index(values ...any) map[string]reflect.Type {
+	types := make(map[string]reflect.Type, len(values))
+	for _, value := range values {
+		t := reflect.TypeOf(value)
+		types[typeName(t)] = t
+	}
+	return types
+}
@@ -0,0 +1,427 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"ecom/internal/validation"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schema is the subset of JSON Schema 2020-12 the generator emits
+type schema struct {
+	Ref                  string     `yaml:"$ref,omitempty"`
+	Type                 schemaType `yaml:"type,omitempty"`
+	Format               string     `yaml:"format,omitempty"`
+	Description          string     `yaml:"description,omitempty"`
+	Enum                 []any      `yaml:"enum,omitempty"`
+	Default              any        `yaml:"default,omitempty"`
+	Minimum              *float64   `yaml:"minimum,omitempty"`
+	ExclusiveMinimum     *float64   `yaml:"exclusiveMinimum,omitempty"`
+	Maximum              *float64   `yaml:"maximum,omitempty"`
+	ExclusiveMaximum     *float64   `yaml:"exclusiveMaximum,omitempty"`
+	MinLength            *int       `yaml:"minLength,omitempty"`
+	MaxLength            *int       `yaml:"maxLength,omitempty"`
+	Pattern              string     `yaml:"pattern,omitempty"`
+	MinItems             *int       `yaml:"minItems,omitempty"`
+	MaxItems             *int       `yaml:"maxItems,omitempty"`
+	Items                *schema    `yaml:"items,omitempty"`
+	Required             []string   `yaml:"required,omitempty"`
+	Properties           properties `yaml:"properties,omitempty"`
+	AdditionalProperties *schema    `yaml:"additionalProperties,omitempty"`
+	AllOf                []*schema  `yaml:"allOf,omitempty"`
+	AnyOf                []*schema  `yaml:"anyOf,omitempty"`
+	Examples             []any      `yaml:"examples,omitempty"`
+}
+
+// schemaType is a JSON Schema type, or a list of them for nullable values
+type schemaType []string
+
+func (t schemaType) MarshalYAML() (any, error) {
+	if len(t) == 1 {
+		return t[0], nil
+	}
+	node := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+	for _, name := range t {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name, Style: quoteNull(name)})
+	}
+	return node, nil
+}
+
+// quoteNull keeps "null" a string instead of the YAML null value
+func quoteNull(name string) yaml.Style {
+	if name == "null" {
+		return yaml.DoubleQuotedStyle
+	}
+	return 0
+}
+
+// property is a named schema; properties keep the order of the struct fields
+type property struct {
+	name   string
+	schema *schema
+}
+
+type properties []property
+
+func (p properties) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, prop := range p {
+		value := &yaml.Node{}
+		if err := value.Encode(prop.schema); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: prop.name}, value)
+	}
+	return node, nil
+}
+
+func refTo(name string) *schema {
+	return &schema{Ref: "#/components/schemas/" + name}
+}
+
+// usage says whether a type describes requests or responses, which decides
+// what makes a field required
+type usage int
+
+const (
+	// inRequest fields are required when their binding tag says so
+	inRequest usage = iota
+	// inResponse fields are required unless they are omitted when empty
+	inResponse
+)
+
+// schemaBuilder turns Go types into component schemas
+type schemaBuilder struct {
+	components map[string]*schema
+	types      map[string]reflect.Type
+	usages     map[string]usage
+	typeDocs   map[string]string
+}
+
+func newSchemaBuilder(typeDocs map[string]string) *schemaBuilder {
+	return &schemaBuilder{
+		components: make(map[string]*schema),
+		types:      make(map[string]reflect.Type),
+		usages:     make(map[string]usage),
+		typeDocs:   typeDocs,
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaFor describes values of type t
+func (b *schemaBuilder) schemaFor(t reflect.Type, use usage) (*schema, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return &schema{Type: schemaType{"string"}, Format: "date-time"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &schema{Type: schemaType{"boolean"}}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &schema{Type: schemaType{"integer"}}, nil
+	case reflect.Int32, reflect.Uint32:
+		return &schema{Type: schemaType{"integer"}, Format: "int32"}, nil
+	case reflect.Int64, reflect.Uint64:
+		return &schema{Type: schemaType{"integer"}, Format: "int64"}, nil
+	case reflect.Float32:
+		return &schema{Type: schemaType{"number"}, Format: "float"}, nil
+	case reflect.Float64:
+		return &schema{Type: schemaType{"number"}, Format: "double"}, nil
+	case reflect.String:
+		return &schema{Type: schemaType{"string"}}, nil
+	case reflect.Interface:
+		// Any JSON value
+		return &schema{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &schema{Type: schemaType{"string"}, Format: "byte"}, nil
+		}
+		items, err := b.schemaFor(t.Elem(), use)
+		if err != nil {
+			return nil, err
+		}
+		return &schema{Type: schemaType{"array"}, Items: items}, nil
+	case reflect.Map:
+		values, err := b.schemaFor(t.Elem(), use)
+		if err != nil {
+			return nil, err
+		}
+		return &schema{Type: schemaType{"object"}, AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return b.component(t, use)
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// component adds the struct type t to the component schemas once and
+// returns a reference to it
+func (b *schemaBuilder) component(t reflect.Type, use usage) (*schema, error) {
+	name := t.Name()
+	if name == "" {
+		return nil, fmt.Errorf("anonymous struct types are not supported")
+	}
+	if existing, ok := b.types[name]; ok {
+		if existing != t {
+			return nil, fmt.Errorf("schema name %s is used by both %s and %s", name, existing, t)
+		}
+		if b.usages[name] != use {
+			return nil, fmt.Errorf("%s is used in both requests and responses", typeName(t))
+		}
+		return refTo(name), nil
+	}
+	b.types[name] = t
+	b.usages[name] = use
+
+	s := &schema{Type: schemaType{"object"}, Description: b.typeDocs[typeName(t)]}
+	if err := b.addFields(s, t, use); err != nil {
+		return nil, fmt.Errorf("%s: %w", typeName(t), err)
+	}
+	b.components[name] = s
+	return refTo(name), nil
+}
+
+// addFields adds the JSON fields of struct t to s, flattening embedded
+// structs the way encoding/json does
+func (b *schemaBuilder) addFields(s *schema, t reflect.Type, use usage) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		omitempty := strings.Contains(opts, "omitempty")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := b.addFields(s, embedded, use); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop, err := b.schemaFor(field.Type, use)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		rules := strings.Split(field.Tag.Get("binding"), ",")
+		if err := applyRules(prop, rules); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if err := applyTags(prop, field.Tag); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		// encoding/json writes null for nil pointers that are not omitted
+		if field.Type.Kind() == reflect.Pointer && !omitempty {
+			prop = nullable(prop)
+		}
+
+		s.Properties = append(s.Properties, property{name: name, schema: prop})
+		if use == inRequest && contains(rules, "required") || use == inResponse && !omitempty {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return nil
+}
+
+// nullable also allows null for the values s describes
+func nullable(s *schema) *schema {
+	if s.Ref != "" {
+		return &schema{AnyOf: []*schema{s, {Type: schemaType{"null"}}}}
+	}
+	if len(s.Type) > 0 {
+		s.Type = append(s.Type, "null")
+	}
+	return s
+}
+
+// applyRules translates validator rules from a binding tag into schema
+// keywords. Rules after dive apply to the items of a slice.
+func applyRules(s *schema, rules []string) error {
+	for i, rule := range rules {
+		tag, param, _ := strings.Cut(rule, "=")
+		kind := s.kind()
+
+		var err error
+		switch tag {
+		case "dive":
+			if s.Items == nil {
+				return fmt.Errorf("dive on a non-slice field")
+			}
+			return applyRules(s.Items, rules[i+1:])
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				typed, convErr := typedValue(kind, value)
+				if convErr != nil {
+					return convErr
+				}
+				s.Enum = append(s.Enum, typed)
+			}
+		case "min", "gte":
+			err = s.lowerBound(param, false)
+		case "gt":
+			err = s.lowerBound(param, true)
+		case "max", "lte":
+			err = s.upperBound(param, false)
+		case "lt":
+			err = s.upperBound(param, true)
+		case "len":
+			if err = s.lowerBound(param, false); err == nil {
+				err = s.upperBound(param, false)
+			}
+		case "email":
+			s.Format = "email"
+		case "url":
+			s.Format = "uri"
+		case "uuid":
+			s.Format = "uuid"
+		case "currency":
+			for _, code := range validation.CurrencyCodes() {
+				s.Enum = append(s.Enum, code)
+			}
+		default:
+			if pattern, ok := validation.Pattern(tag); ok {
+				s.Pattern = pattern
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("rule %s: %w", rule, err)
+		}
+	}
+	return nil
+}
+
+// lowerBound applies min/gte/gt: a length for strings, a count for arrays
+// and a value for numbers
+func (s *schema) lowerBound(param string, exclusive bool) error {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return err
+	}
+	switch s.kind() {
+	case "string":
+		s.MinLength = intPtr(int(n), exclusive)
+	case "array":
+		s.MinItems = intPtr(int(n), exclusive)
+	case "integer", "number":
+		if exclusive {
+			s.ExclusiveMinimum = &n
+		} else {
+			s.Minimum = &n
+		}
+	}
+	return nil
+}
+
+// upperBound applies max/lte/lt
+func (s *schema) upperBound(param string, exclusive bool) error {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return err
+	}
+	switch s.kind() {
+	case "string":
+		s.MaxLength = intPtr(int(n), false)
+		if exclusive {
+			*s.MaxLength--
+		}
+	case "array":
+		s.MaxItems = intPtr(int(n), false)
+		if exclusive {
+			*s.MaxItems--
+		}
+	case "integer", "number":
+		if exclusive {
+			s.ExclusiveMaximum = &n
+		} else {
+			s.Maximum = &n
+		}
+	}
+	return nil
+}
+
+// applyTags documents the default and example struct tags
+func applyTags(s *schema, tag reflect.StructTag) error {
+	if value, ok := tag.Lookup("default"); ok {
+		typed, err := typedValue(s.kind(), value)
+		if err != nil {
+			return fmt.Errorf("default: %w", err)
+		}
+		s.Default = typed
+	}
+	if value, ok := tag.Lookup("example"); ok {
+		typed, err := typedValue(s.kind(), value)
+		if err != nil {
+			return fmt.Errorf("example: %w", err)
+		}
+		s.Examples = []any{typed}
+	}
+	return nil
+}
+
+// kind is the first JSON type of s, or "" for references and any value
+func (s *schema) kind() string {
+	if len(s.Type) == 0 {
+		return ""
+	}
+	return s.Type[0]
+}
+
+// typedValue converts a tag value to the JSON type of the field
+func typedValue(kind, value string) (any, error) {
+	switch kind {
+	case "integer":
+		return strconv.ParseInt(value, 10, 64)
+	case "number":
+		return strconv.ParseFloat(value, 64)
+	case "boolean":
+		return strconv.ParseBool(value)
+	case "string", "":
+		return value, nil
+	}
+	var v any
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return nil, fmt.Errorf("%q is not a %s", value, kind)
+	}
+	return v, nil
+}
+
+func intPtr(n int, exclusive bool) *int {
+	if exclusive {
+		n++
+	}
+	return &n
+}
+
+func contains(items []string, item string) bool {
+	for _, candidate := range items {
+		if candidate == item {
+			return true
+		}
+	}
+	return false
+}
+
+// typeName is the package-qualified name annotations use: "dto.ProductResponse"
+func typeName(t reflect.Type) string {
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
@@ -0,0 +1,217 @@
+// Package openapi generates docs/swagger.yaml from the code and checks the
+// running API against it. Generate builds the spec from the route table and
+// the handlers' annotations; the contract tests use Validator to fail CI when
+// a route or a body drifts from it, and the server can run Middleware to log
+// violations in development.
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+
+	"ecom/internal/logging"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+)
+
+// Validator validates requests and responses against an OpenAPI document
+type Validator struct {
+	doc    *openapi3.T
+	router routers.Router
+}
+
+// Load reads and validates the OpenAPI document at path
+func Load(path string) (*Validator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI spec: %w", err)
+	}
+	v, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return v, nil
+}
+
+// Parse validates an OpenAPI 3.0 or 3.1 document
+func Parse(data []byte) (*Validator, error) {
+	data, err := downgrade(data)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI spec: %w", err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+	}
+
+	// Match operations on any host: the documented servers are only examples
+	doc.Servers = nil
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenAPI router: %w", err)
+	}
+	return &Validator{doc: doc, router: router}, nil
+}
+
+// ginParam matches :name and *name segments of a gin route
+var ginParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// UndocumentedRoutes returns "METHOD /path" for every route that has no
+// operation in the spec. Routes whose path is in skip are ignored.
+func (v *Validator) UndocumentedRoutes(routes gin.RoutesInfo, skip ...string) []string {
+	skipped := make(map[string]bool, len(skip))
+	for _, path := range skip {
+		skipped[path] = true
+	}
+
+	var missing []string
+	for _, route := range routes {
+		if skipped[route.Path] {
+			continue
+		}
+		path := ginParam.ReplaceAllString(route.Path, "{$1}")
+		item := v.doc.Paths.Find(path)
+		if item == nil || item.GetOperation(route.Method) == nil {
+			missing = append(missing, route.Method+" "+path)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// ValidateRequest checks the parameters and body of req against its
+// operation. The body is restored so req can still be served afterwards.
+func (v *Validator) ValidateRequest(req *http.Request) error {
+	input, err := v.requestInput(req)
+	if err != nil {
+		return err
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+	defer func() { req.Body = io.NopCloser(bytes.NewReader(body)) }()
+
+	return openapi3filter.ValidateRequest(req.Context(), input)
+}
+
+// ValidateResponse checks a response to req against the responses declared
+// by its operation. Statuses the operation does not declare are violations.
+func (v *Validator) ValidateResponse(req *http.Request, status int, header http.Header, body []byte) error {
+	input, err := v.requestInput(req)
+	if err != nil {
+		return err
+	}
+
+	response := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 status,
+		Header:                 header,
+		Options:                input.Options,
+	}
+	response.SetBodyBytes(body)
+	return openapi3filter.ValidateResponse(context.Background(), response)
+}
+
+func (v *Validator) requestInput(req *http.Request) (*openapi3filter.RequestValidationInput, error) {
+	route, pathParams, err := v.router.FindRoute(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, err)
+	}
+	return &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+			MultiError:            true,
+			AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+		},
+	}, nil
+}
+
+// Middleware logs requests and responses that violate the spec. It never
+// changes the response, so it is safe to enable outside of tests; requests
+// the API rejects with 4xx are expected to violate it and are not logged.
+func (v *Validator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := c.Request
+		// CORS preflights are not API operations
+		if req.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+		body, err := readBody(req)
+		if err != nil {
+			c.Next()
+			return
+		}
+		// Validate a copy: the handler reads (and closes) the original body
+		check := req.Clone(req.Context())
+		check.Body = io.NopCloser(bytes.NewReader(body))
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status >= 400 && status < 500 {
+			return
+		}
+		if err := v.ValidateRequest(check); err != nil {
+			logging.FromContext(req.Context()).Warn("OpenAPI request violation", slog.Any("error", err))
+		}
+		if err := v.ValidateResponse(check, status, recorder.Header(), recorder.body.Bytes()); err != nil {
+			logging.FromContext(req.Context()).Warn("OpenAPI response violation", slog.Int("status", status), slog.Any("error", err))
+		}
+	}
+}
+
+// responseRecorder keeps a copy of the response body
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// readBody drains req.Body and replaces it with a reader over the same bytes
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
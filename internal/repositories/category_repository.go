@@ -0,0 +1,264 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"ecom/internal/apperrors"
+	"ecom/internal/database"
+	"ecom/internal/dto"
+	"ecom/internal/logging"
+	"ecom/internal/models"
+)
+
+const categoryColumns = `id, name, slug, description, parent_id, image_url, is_active, sort_order, created_at, updated_at`
+
+// categoryRepository is the Postgres implementation of CategoryRepository
+type categoryRepository struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+}
+
+// NewCategoryRepository creates a new category repository
+func NewCategoryRepository(db *sql.DB, queryTimeout time.Duration) CategoryRepository {
+	return &categoryRepository{db: db, queryTimeout: queryTimeout}
+}
+
+// Create inserts a category and returns its ID
+func (r *categoryRepository) Create(ctx context.Context, req *dto.CreateCategoryRequest) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var id int64
+
+	query := `
+		INSERT INTO categories (name, slug, description, parent_id, image_url, is_active, sort_order, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id
+	`
+
+	err := database.Conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		req.Name,
+		req.Slug,
+		req.Description,
+		req.ParentID,
+		req.ImageURL,
+		req.IsActive,
+		req.SortOrder,
+	).Scan(&id)
+
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating category", slog.Any("error", err))
+		return 0, fmt.Errorf("failed to create category: %w", apperrors.FromDB(err))
+	}
+
+	return id, nil
+}
+
+// GetByID retrieves a category that has not been deleted
+func (r *categoryRepository) GetByID(ctx context.Context, id int64) (*models.Category, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + categoryColumns + `
+		FROM categories
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	category, err := scanCategory(database.Conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NotFound("category")
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching category", slog.Any("error", err))
+		return nil, fmt.Errorf("failed to fetch category: %w", apperrors.FromDB(err))
+	}
+
+	return category, nil
+}
+
+// List retrieves a page of categories ordered by sort_order
+func (r *categoryRepository) List(ctx context.Context, limit, offset int) ([]models.Category, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + categoryColumns + `
+		FROM categories
+		WHERE deleted_at IS NULL
+		ORDER BY sort_order ASC, id ASC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching categories", slog.Any("error", err))
+		return nil, fmt.Errorf("failed to fetch categories: %w", apperrors.FromDB(err))
+	}
+	defer rows.Close()
+
+	var categories []models.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			logging.FromContext(ctx).Error("error scanning category", slog.Any("error", err))
+			return nil, fmt.Errorf("failed to scan category: %w", apperrors.FromDB(err))
+		}
+		categories = append(categories, *category)
+	}
+
+	if err = rows.Err(); err != nil {
+		logging.FromContext(ctx).Error("error iterating categories", slog.Any("error", err))
+		return nil, fmt.Errorf("error iterating categories: %w", apperrors.FromDB(err))
+	}
+
+	return categories, nil
+}
+
+// Count returns the number of categories that have not been deleted
+func (r *categoryRepository) Count(ctx context.Context) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var total int
+	if err := database.Conn(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM categories WHERE deleted_at IS NULL`).Scan(&total); err != nil {
+		logging.FromContext(ctx).Error("error counting categories", slog.Any("error", err))
+		return 0, fmt.Errorf("failed to count categories: %w", apperrors.FromDB(err))
+	}
+	return total, nil
+}
+
+// Update applies the provided fields of req to a category
+func (r *categoryRepository) Update(ctx context.Context, id int64, req *dto.UpdateCategoryRequest) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	// Build dynamic query based on provided fields
+	query := `
+		UPDATE categories
+		SET `
+
+	args := []interface{}{}
+	argNum := 1
+
+	if req.Name != "" {
+		query += fmt.Sprintf("name = $%d, ", argNum)
+		args = append(args, req.Name)
+		argNum++
+	}
+
+	if req.Slug != "" {
+		query += fmt.Sprintf("slug = $%d, ", argNum)
+		args = append(args, req.Slug)
+		argNum++
+	}
+
+	if req.Description != "" {
+		query += fmt.Sprintf("description = $%d, ", argNum)
+		args = append(args, req.Description)
+		argNum++
+	}
+
+	if req.ParentID != nil {
+		query += fmt.Sprintf("parent_id = $%d, ", argNum)
+		args = append(args, req.ParentID)
+		argNum++
+	}
+
+	if req.ImageURL != "" {
+		query += fmt.Sprintf("image_url = $%d, ", argNum)
+		args = append(args, req.ImageURL)
+		argNum++
+	}
+
+	if req.IsActive != nil {
+		query += fmt.Sprintf("is_active = $%d, ", argNum)
+		args = append(args, req.IsActive)
+		argNum++
+	}
+
+	if req.SortOrder != nil {
+		query += fmt.Sprintf("sort_order = $%d, ", argNum)
+		args = append(args, req.SortOrder)
+		argNum++
+	}
+
+	// Add updated_at
+	query += fmt.Sprintf("updated_at = CURRENT_TIMESTAMP WHERE id = $%d AND deleted_at IS NULL", argNum)
+	args = append(args, id)
+
+	result, err := database.Conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating category", slog.Any("error", err))
+		return fmt.Errorf("failed to update category: %w", apperrors.FromDB(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logging.FromContext(ctx).Error("error getting rows affected", slog.Any("error", err))
+		return fmt.Errorf("failed to get rows affected: %w", apperrors.FromDB(err))
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound("category")
+	}
+
+	return nil
+}
+
+// Delete soft deletes a category
+func (r *categoryRepository) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE categories
+		SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := database.Conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting category", slog.Any("error", err))
+		return fmt.Errorf("failed to delete category: %w", apperrors.FromDB(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logging.FromContext(ctx).Error("error getting rows affected", slog.Any("error", err))
+		return fmt.Errorf("failed to get rows affected: %w", apperrors.FromDB(err))
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound("category")
+	}
+
+	return nil
+}
+
+// scanCategory scans a row selected with categoryColumns
+func scanCategory(row rowScanner) (*models.Category, error) {
+	var category models.Category
+	err := row.Scan(
+		&category.ID,
+		&category.Name,
+		&category.Slug,
+		&category.Description,
+		&category.ParentID,
+		&category.ImageURL,
+		&category.IsActive,
+		&category.SortOrder,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
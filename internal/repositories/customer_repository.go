@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"ecom/internal/apperrors"
+	"ecom/internal/database"
+	"ecom/internal/logging"
+	"ecom/internal/models"
+)
+
+const customerColumns = `id, email, first_name, last_name, phone, date_of_birth, is_active, email_verified_at, last_login_at, COALESCE(password_hash, ''), created_at, updated_at`
+
+// customerRepository is the Postgres implementation of CustomerRepository
+type customerRepository struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+}
+
+// NewCustomerRepository creates a new customer repository
+func NewCustomerRepository(db *sql.DB, queryTimeout time.Duration) CustomerRepository {
+	return &customerRepository{db: db, queryTimeout: queryTimeout}
+}
+
+// Create inserts a customer and returns its ID
+func (r *customerRepository) Create(ctx context.Context, customer *models.Customer) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var id int64
+
+	query := `
+		INSERT INTO customers (email, first_name, last_name, phone, date_of_birth, is_active, password_hash, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id
+	`
+
+	err := database.Conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		customer.Email,
+		customer.FirstName,
+		customer.LastName,
+		customer.Phone,
+		customer.DateOfBirth,
+		customer.IsActive,
+		customer.PasswordHash,
+	).Scan(&id)
+
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating customer", slog.Any("error", err))
+		return 0, fmt.Errorf("failed to create customer: %w", apperrors.FromDB(err))
+	}
+
+	return id, nil
+}
+
+// GetByID retrieves a customer that has not been deleted
+func (r *customerRepository) GetByID(ctx context.Context, id int64) (*models.Customer, error) {
+	return r.get(ctx, `id = $1`, id)
+}
+
+// GetByEmail retrieves a customer that has not been deleted by email
+func (r *customerRepository) GetByEmail(ctx context.Context, email string) (*models.Customer, error) {
+	return r.get(ctx, `lower(email) = $1`, email)
+}
+
+func (r *customerRepository) get(ctx context.Context, where string, arg any) (*models.Customer, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + customerColumns + `
+		FROM customers
+		WHERE ` + where + ` AND deleted_at IS NULL
+	`
+
+	customer, err := scanCustomer(database.Conn(ctx, r.db).QueryRowContext(ctx, query, arg))
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NotFound("customer")
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching customer", slog.Any("error", err))
+		return nil, fmt.Errorf("failed to fetch customer: %w", apperrors.FromDB(err))
+	}
+
+	return customer, nil
+}
+
+// UpdatePasswordHash replaces a customer's password hash
+func (r *customerRepository) UpdatePasswordHash(ctx context.Context, id int64, hash string) error {
+	return r.exec(ctx, "updating customer password", `UPDATE customers SET password_hash = $2 WHERE id = $1 AND deleted_at IS NULL`, id, hash)
+}
+
+// RecordLogin sets a customer's last_login_at to now
+func (r *customerRepository) RecordLogin(ctx context.Context, id int64) error {
+	return r.exec(ctx, "recording customer login", `UPDATE customers SET last_login_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`, id)
+}
+
+// exec runs an update of one customer; action names it in errors
+func (r *customerRepository) exec(ctx context.Context, action, query string, args ...any) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := database.Conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error("error "+action, slog.Any("error", err))
+		return fmt.Errorf("failed %s: %w", action, apperrors.FromDB(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.NotFound("customer")
+	}
+
+	return nil
+}
+
+// scanCustomer scans a row selected with customerColumns
+func scanCustomer(row rowScanner) (*models.Customer, error) {
+	var customer models.Customer
+	err := row.Scan(
+		&customer.ID,
+		&customer.Email,
+		&customer.FirstName,
+		&customer.LastName,
+		&customer.Phone,
+		&customer.DateOfBirth,
+		&customer.IsActive,
+		&customer.EmailVerifiedAt,
+		&customer.LastLoginAt,
+		&customer.PasswordHash,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
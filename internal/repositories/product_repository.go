@@ -0,0 +1,344 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"ecom/internal/apperrors"
+	"ecom/internal/database"
+	"ecom/internal/dto"
+	"ecom/internal/logging"
+	"ecom/internal/models"
+)
+
+const productColumns = `id, sku, name, slug, description, short_description, category_id, status, price,
+	compare_at_price, cost_price, stock_quantity, low_stock_threshold, weight_kg,
+	dimensions_cm, barcode, manufacturer, brand, rating_average, rating_count,
+	view_count, is_featured, meta_title, meta_description, created_at, updated_at`
+
+// productRepository is the Postgres implementation of ProductRepository
+type productRepository struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+}
+
+// NewProductRepository creates a new product repository. Each call is bounded
+// by queryTimeout in addition to the caller's deadline.
+func NewProductRepository(db *sql.DB, queryTimeout time.Duration) ProductRepository {
+	return &productRepository{db: db, queryTimeout: queryTimeout}
+}
+
+// Create inserts a product with no stock and returns its ID. The opening
+// stock is recorded with SetStock, as an inventory movement.
+func (r *productRepository) Create(ctx context.Context, req *dto.CreateProductRequest) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var id int64
+
+	query := `
+		INSERT INTO products (sku, name, slug, description, short_description, category_id, status, price, compare_at_price, stock_quantity, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, 0, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id
+	`
+	err := database.Conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		req.SKU,
+		req.Name,
+		req.Slug,
+		req.Description,
+		req.ShortDescription,
+		req.CategoryID,
+		req.Status,
+		req.Price,
+		req.CompareAtPrice,
+	).Scan(&id)
+
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating product", slog.Any("error", err))
+		return 0, fmt.Errorf("failed to create product: %w", apperrors.FromDB(err))
+	}
+
+	return id, nil
+}
+
+// GetByID retrieves a product that has not been deleted
+func (r *productRepository) GetByID(ctx context.Context, id int64) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + productColumns + `
+		FROM products
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	product, err := scanProduct(database.Conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NotFound("product")
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching product by ID", slog.Any("error", err))
+		return nil, fmt.Errorf("failed to fetch product: %w", apperrors.FromDB(err))
+	}
+
+	return product, nil
+}
+
+// List retrieves a page of products, newest first
+func (r *productRepository) List(ctx context.Context, limit, offset int) ([]models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + productColumns + `
+		FROM products
+		WHERE deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2`
+
+	return r.query(ctx, query, limit, offset)
+}
+
+// Count returns the number of products that have not been deleted
+func (r *productRepository) Count(ctx context.Context) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var total int
+	if err := database.Conn(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM products WHERE deleted_at IS NULL`).Scan(&total); err != nil {
+		logging.FromContext(ctx).Error("error counting products", slog.Any("error", err))
+		return 0, fmt.Errorf("failed to count products: %w", apperrors.FromDB(err))
+	}
+	return total, nil
+}
+
+// ListByCategory retrieves a page of a category's products, newest first
+func (r *productRepository) ListByCategory(ctx context.Context, categoryID int64, limit, offset int) ([]models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + productColumns + `
+		FROM products
+		WHERE category_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	return r.query(ctx, query, categoryID, limit, offset)
+}
+
+// CountByCategory returns the number of products in a category
+func (r *productRepository) CountByCategory(ctx context.Context, categoryID int64) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM products WHERE category_id = $1 AND deleted_at IS NULL`
+	if err := database.Conn(ctx, r.db).QueryRowContext(ctx, countQuery, categoryID).Scan(&total); err != nil {
+		logging.FromContext(ctx).Error("error counting products", slog.Any("error", err))
+		return 0, fmt.Errorf("failed to count products: %w", apperrors.FromDB(err))
+	}
+	return total, nil
+}
+
+// Update applies the non-empty fields of req to a product, except the stock
+// quantity, which only changes through SetStock
+func (r *productRepository) Update(ctx context.Context, id int64, req *dto.UpdateProductRequest) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE products SET
+			name = COALESCE(NULLIF($1, ''), name),
+			slug = COALESCE(NULLIF($2, ''), slug),
+			description = COALESCE(NULLIF($3, ''), description),
+			short_description = COALESCE(NULLIF($4, ''), short_description),
+			category_id = COALESCE($5, category_id),
+			status = COALESCE(NULLIF($6, ''), status),
+			price = COALESCE($7, price),
+			compare_at_price = COALESCE($8, compare_at_price),
+			low_stock_threshold = COALESCE($9, low_stock_threshold),
+			weight_kg = COALESCE($10, weight_kg),
+			dimensions_cm = COALESCE(NULLIF($11, ''), dimensions_cm),
+			brand = COALESCE(NULLIF($12, ''), brand),
+			is_featured = COALESCE($13, is_featured),
+			meta_title = COALESCE(NULLIF($14, ''), meta_title),
+			meta_description = COALESCE(NULLIF($15, ''), meta_description),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $16 AND deleted_at IS NULL
+		RETURNING id
+	`
+	var updatedID int64
+	err := database.Conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		req.Name,
+		req.Slug,
+		req.Description,
+		req.ShortDescription,
+		req.CategoryID,
+		req.Status,
+		req.Price,
+		req.CompareAtPrice,
+		req.LowStockThreshold,
+		req.WeightKg,
+		req.DimensionsCm,
+		req.Brand,
+		req.IsFeautred,
+		req.MetaTitle,
+		req.MetaDescription,
+		id,
+	).Scan(&updatedID)
+
+	if err == sql.ErrNoRows {
+		return apperrors.NotFound("product")
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating product", slog.Any("error", err))
+		return fmt.Errorf("failed to update product: %w", apperrors.FromDB(err))
+	}
+
+	return nil
+}
+
+// Delete soft deletes a product
+func (r *productRepository) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE products
+		SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := database.Conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting product", slog.Any("error", err))
+		return fmt.Errorf("failed to delete product: %w", apperrors.FromDB(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logging.FromContext(ctx).Error("error getting rows affected", slog.Any("error", err))
+		return fmt.Errorf("failed to get rows affected: %w", apperrors.FromDB(err))
+	}
+
+	if rowsAffected == 0 {
+		return apperrors.NotFound("product")
+	}
+
+	return nil
+}
+
+// SetStock sets a product's stock quantity by recording an adjustment
+// inventory movement of the difference; the movement's trigger updates the
+// product. The product row is locked first, so concurrent changes see each
+// other's result. It returns the difference, and records nothing when it
+// is zero.
+func (r *productRepository) SetStock(ctx context.Context, id int64, quantity int, notes, createdBy string) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	conn := database.Conn(ctx, r.db)
+
+	var current int
+	err := conn.QueryRowContext(ctx, `
+		SELECT stock_quantity
+		FROM products
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`, id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return 0, apperrors.NotFound("product")
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("error locking product stock", slog.Any("error", err))
+		return 0, fmt.Errorf("failed to lock product stock: %w", apperrors.FromDB(err))
+	}
+
+	delta := quantity - current
+	if delta == 0 {
+		return 0, nil
+	}
+
+	query := `
+		INSERT INTO inventory_movements (product_id, movement_type, quantity, reference_type, notes, created_by, created_at)
+		VALUES ($1, 'adjustment', $2, 'adjustment', NULLIF($3, ''), NULLIF($4, ''), CURRENT_TIMESTAMP)
+	`
+	if _, err := conn.ExecContext(ctx, query, id, delta, notes, createdBy); err != nil {
+		logging.FromContext(ctx).Error("error recording inventory movement", slog.Any("error", err))
+		return 0, fmt.Errorf("failed to record inventory movement: %w", apperrors.FromDB(err))
+	}
+
+	return delta, nil
+}
+
+func (r *productRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.Product, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching products", slog.Any("error", err))
+		return nil, fmt.Errorf("failed to fetch products: %w", apperrors.FromDB(err))
+	}
+	defer rows.Close()
+
+	var products []models.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			logging.FromContext(ctx).Error("error scanning product row", slog.Any("error", err))
+			return nil, fmt.Errorf("failed to scan product: %w", apperrors.FromDB(err))
+		}
+		products = append(products, *product)
+	}
+
+	if err = rows.Err(); err != nil {
+		logging.FromContext(ctx).Error("error iterating products", slog.Any("error", err))
+		return nil, fmt.Errorf("row iteration error: %w", apperrors.FromDB(err))
+	}
+
+	return products, nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanProduct scans a row selected with productColumns
+func scanProduct(row rowScanner) (*models.Product, error) {
+	var product models.Product
+	err := row.Scan(
+		&product.ID,
+		&product.SKU,
+		&product.Name,
+		&product.Slug,
+		&product.Description,
+		&product.ShortDescription,
+		&product.CategoryID,
+		&product.Status,
+		&product.Price,
+		&product.CompareAtPrice,
+		&product.CostPrice,
+		&product.StockQuantity,
+		&product.LowStockThreshold,
+		&product.WeightKg,
+		&product.DimensionsCm,
+		&product.Barcode,
+		&product.Manufacturer,
+		&product.Brand,
+		&product.RatingAverage,
+		&product.RatingCount,
+		&product.ViewCount,
+		&product.IsFeatured,
+		&product.MetaTitle,
+		&product.MetaDescription,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"ecom/internal/apperrors"
+	"ecom/internal/database"
+	"ecom/internal/logging"
+	"ecom/internal/models"
+)
+
+// refreshTokenRepository is the Postgres implementation of
+// RefreshTokenRepository
+type refreshTokenRepository struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository
+func NewRefreshTokenRepository(db *sql.DB, queryTimeout time.Duration) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db, queryTimeout: queryTimeout}
+}
+
+// Create stores a token and returns its family
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) (string, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var familyID string
+
+	query := `
+		INSERT INTO refresh_tokens (token_hash, family_id, customer_id, staff_user_id, expires_at, created_at)
+		VALUES ($1, COALESCE(NULLIF($2, '')::uuid, uuid_generate_v4()), $3, $4, $5, CURRENT_TIMESTAMP)
+		RETURNING family_id
+	`
+
+	err := database.Conn(ctx, r.db).QueryRowContext(
+		ctx,
+		query,
+		token.TokenHash,
+		token.FamilyID,
+		token.CustomerID,
+		token.StaffUserID,
+		token.ExpiresAt,
+	).Scan(&familyID)
+
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating refresh token", slog.Any("error", err))
+		return "", fmt.Errorf("failed to create refresh token: %w", apperrors.FromDB(err))
+	}
+
+	return familyID, nil
+}
+
+// GetByHashForUpdate retrieves a token by hash and locks it
+func (r *refreshTokenRepository) GetByHashForUpdate(ctx context.Context, hash []byte) (*models.RefreshToken, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT id, token_hash, family_id, customer_id, staff_user_id, expires_at, rotated_at, revoked_at, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+		FOR UPDATE
+	`
+
+	var token models.RefreshToken
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, query, hash).Scan(
+		&token.ID,
+		&token.TokenHash,
+		&token.FamilyID,
+		&token.CustomerID,
+		&token.StaffUserID,
+		&token.ExpiresAt,
+		&token.RotatedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NotFound("refresh token")
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching refresh token", slog.Any("error", err))
+		return nil, fmt.Errorf("failed to fetch refresh token: %w", apperrors.FromDB(err))
+	}
+
+	return &token, nil
+}
+
+// MarkRotated records that a token was exchanged for its successor
+func (r *refreshTokenRepository) MarkRotated(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `UPDATE refresh_tokens SET rotated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, query, id); err != nil {
+		logging.FromContext(ctx).Error("error rotating refresh token", slog.Any("error", err))
+		return fmt.Errorf("failed to rotate refresh token: %w", apperrors.FromDB(err))
+	}
+
+	return nil
+}
+
+// RevokeFamily revokes every unrevoked token of a family
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`
+
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, query, familyID); err != nil {
+		logging.FromContext(ctx).Error("error revoking refresh tokens", slog.Any("error", err))
+		return fmt.Errorf("failed to revoke refresh tokens: %w", apperrors.FromDB(err))
+	}
+
+	return nil
+}
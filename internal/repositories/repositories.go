@@ -1,90 +1,101 @@
 package repositories
 
 import (
-	"database/sql"
-	"ecom/internal/database"
+	"context"
+	"time"
+
+	"ecom/internal/dto"
 	"ecom/internal/models"
-	"fmt"
 )
 
-// UserRepository handles user-related database operations
-type UserRepository struct {
-	db *sql.DB
+// ===========================
+// Repository Interfaces
+// ===========================
+
+// Repositories return apperrors.NotFound for missing rows and translate
+// constraint violations and query timeouts with apperrors.FromDB, so services
+// can pass their errors through unchanged. They query through database.Conn,
+// so a call made inside TxManager.WithinTx joins the caller's transaction.
+
+// UserRepository persists users
+type UserRepository interface {
+	GetByID(ctx context.Context, id int) (*models.User, error)
 }
 
-// NewUserRepository creates a new user repository
-func NewUserRepository() *UserRepository {
-	return &UserRepository{
-		db: database.GetDB(),
-	}
+// CategoryRepository persists categories
+type CategoryRepository interface {
+	Create(ctx context.Context, req *dto.CreateCategoryRequest) (int64, error)
+	GetByID(ctx context.Context, id int64) (*models.Category, error)
+	List(ctx context.Context, limit, offset int) ([]models.Category, error)
+	Count(ctx context.Context) (int, error)
+	Update(ctx context.Context, id int64, req *dto.UpdateCategoryRequest) error
+	Delete(ctx context.Context, id int64) error
 }
 
-// GetUserByID retrieves a user by ID
-func (r *UserRepository) GetUserByID(id int) (*models.User, error) {
-	query := `SELECT id, email, first_name, last_name, created_at, updated_at 
-	          FROM users WHERE id = $1`
-	
-	user := &models.User{}
-	err := r.db.QueryRow(query, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.FirstName,
-		&user.LastName,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-	
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
-		}
-		return nil, err
-	}
-	
-	return user, nil
+// ProductRepository persists products
+type ProductRepository interface {
+	Create(ctx context.Context, req *dto.CreateProductRequest) (int64, error)
+	GetByID(ctx context.Context, id int64) (*models.Product, error)
+	List(ctx context.Context, limit, offset int) ([]models.Product, error)
+	Count(ctx context.Context) (int, error)
+	ListByCategory(ctx context.Context, categoryID int64, limit, offset int) ([]models.Product, error)
+	CountByCategory(ctx context.Context, categoryID int64) (int, error)
+	Update(ctx context.Context, id int64, req *dto.UpdateProductRequest) error
+	// SetStock records an adjustment movement to quantity and returns its
+	// signed size
+	SetStock(ctx context.Context, id int64, quantity int, notes, createdBy string) (int, error)
+	Delete(ctx context.Context, id int64) error
 }
 
-// ProductRepository handles product-related database operations
-type ProductRepository struct {
-	db *sql.DB
+// CustomerRepository persists customers. Emails are matched
+// case-insensitively; callers pass them lower-cased.
+type CustomerRepository interface {
+	Create(ctx context.Context, customer *models.Customer) (int64, error)
+	GetByID(ctx context.Context, id int64) (*models.Customer, error)
+	GetByEmail(ctx context.Context, email string) (*models.Customer, error)
+	UpdatePasswordHash(ctx context.Context, id int64, hash string) error
+	RecordLogin(ctx context.Context, id int64) error
 }
 
-// NewProductRepository creates a new product repository
-func NewProductRepository() *ProductRepository {
-	return &ProductRepository{
-		db: database.GetDB(),
-	}
+// StaffUserRepository persists staff accounts
+type StaffUserRepository interface {
+	Create(ctx context.Context, user *models.StaffUser) (int64, error)
+	GetByID(ctx context.Context, id int64) (*models.StaffUser, error)
+	GetByEmail(ctx context.Context, email string) (*models.StaffUser, error)
+	UpdatePasswordHash(ctx context.Context, id int64, hash string) error
+	RecordLogin(ctx context.Context, id int64) error
 }
 
-// GetAllProducts retrieves all products
-func (r *ProductRepository) GetAllProducts() ([]models.Product, error) {
-	query := `SELECT id, name, description, price, stock, created_at, updated_at 
-	          FROM products ORDER BY created_at DESC`
-	
-	rows, err := r.db.Query(query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	
-	var products []models.Product
-	for rows.Next() {
-		var product models.Product
-		err := rows.Scan(
-			&product.ID,
-			&product.Name,
-			&product.Description,
-			&product.Price,
-			&product.Stock,
-			&product.CreatedAt,
-			&product.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		products = append(products, product)
-	}
-	
-	return products, nil
+// RefreshTokenRepository persists refresh tokens by hash
+type RefreshTokenRepository interface {
+	// Create stores a token and returns its family, a new one when
+	// token.FamilyID is empty
+	Create(ctx context.Context, token *models.RefreshToken) (familyID string, err error)
+	// GetByHashForUpdate locks the token until the transaction ends, so
+	// concurrent refreshes with one token are serialized
+	GetByHashForUpdate(ctx context.Context, hash []byte) (*models.RefreshToken, error)
+	MarkRotated(ctx context.Context, id int64) error
+	// RevokeFamily revokes every token of a family not yet revoked
+	RevokeFamily(ctx context.Context, familyID string) error
+}
+
+// RoleRepository reads the roles and permissions tables
+type RoleRepository interface {
+	// PermissionsOfRole returns the permissions a role grants, sorted
+	PermissionsOfRole(ctx context.Context, role string) ([]string, error)
+	// PermissionsOfStaffUser returns the permissions of a staff user's
+	// roles, sorted and without duplicates
+	PermissionsOfStaffUser(ctx context.Context, staffUserID int64) ([]string, error)
+	// AssignToStaffUser grants roles to a staff user; an unknown role is a
+	// validation error
+	AssignToStaffUser(ctx context.Context, staffUserID int64, roles []string) error
 }
 
+// withTimeout bounds a single repository call by the per-query deadline. An
+// earlier deadline on ctx (the request timeout) still takes precedence.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"ecom/internal/apperrors"
+	"ecom/internal/database"
+	"ecom/internal/logging"
+)
+
+// roleRepository is the Postgres implementation of RoleRepository
+type roleRepository struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository(db *sql.DB, queryTimeout time.Duration) RoleRepository {
+	return &roleRepository{db: db, queryTimeout: queryTimeout}
+}
+
+// PermissionsOfRole returns the permissions a role grants
+func (r *roleRepository) PermissionsOfRole(ctx context.Context, role string) ([]string, error) {
+	query := `
+		SELECT p.name
+		FROM permissions p
+		JOIN role_permissions rp ON rp.permission_id = p.id
+		JOIN roles r ON r.id = rp.role_id
+		WHERE r.name = $1
+		ORDER BY p.name
+	`
+	return r.permissions(ctx, query, role)
+}
+
+// PermissionsOfStaffUser returns the permissions of a staff user's roles
+func (r *roleRepository) PermissionsOfStaffUser(ctx context.Context, staffUserID int64) ([]string, error) {
+	query := `
+		SELECT DISTINCT p.name
+		FROM permissions p
+		JOIN role_permissions rp ON rp.permission_id = p.id
+		JOIN staff_user_roles sur ON sur.role_id = rp.role_id
+		WHERE sur.staff_user_id = $1
+		ORDER BY p.name
+	`
+	return r.permissions(ctx, query, staffUserID)
+}
+
+func (r *roleRepository) permissions(ctx context.Context, query string, arg any) ([]string, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, arg)
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching permissions", slog.Any("error", err))
+		return nil, fmt.Errorf("failed to fetch permissions: %w", apperrors.FromDB(err))
+	}
+	defer rows.Close()
+
+	var permissions []string
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			logging.FromContext(ctx).Error("error scanning permission", slog.Any("error", err))
+			return nil, fmt.Errorf("failed to scan permission: %w", apperrors.FromDB(err))
+		}
+		permissions = append(permissions, permission)
+	}
+
+	if err = rows.Err(); err != nil {
+		logging.FromContext(ctx).Error("error iterating permissions", slog.Any("error", err))
+		return nil, fmt.Errorf("error iterating permissions: %w", apperrors.FromDB(err))
+	}
+
+	return permissions, nil
+}
+
+// AssignToStaffUser grants roles to a staff user. Roles it already holds
+// are skipped.
+func (r *roleRepository) AssignToStaffUser(ctx context.Context, staffUserID int64, roles []string) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	conn := database.Conn(ctx, r.db)
+	for _, role := range roles {
+		var roleID int64
+		err := conn.QueryRowContext(ctx, `SELECT id FROM roles WHERE name = $1`, role).Scan(&roleID)
+		if err == sql.ErrNoRows {
+			return apperrors.Validation(fmt.Sprintf("unknown role %q", role), "role")
+		}
+		if err != nil {
+			logging.FromContext(ctx).Error("error fetching role", slog.Any("error", err))
+			return fmt.Errorf("failed to fetch role: %w", apperrors.FromDB(err))
+		}
+
+		query := `
+			INSERT INTO staff_user_roles (staff_user_id, role_id, created_at)
+			VALUES ($1, $2, CURRENT_TIMESTAMP)
+			ON CONFLICT DO NOTHING
+		`
+		if _, err := conn.ExecContext(ctx, query, staffUserID, roleID); err != nil {
+			logging.FromContext(ctx).Error("error assigning role", slog.Any("error", err))
+			return fmt.Errorf("failed to assign role: %w", apperrors.FromDB(err))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"ecom/internal/apperrors"
+	"ecom/internal/database"
+	"ecom/internal/logging"
+	"ecom/internal/models"
+)
+
+const staffUserColumns = `id, email, name, password_hash, is_active, last_login_at, created_at, updated_at`
+
+// staffUserRepository is the Postgres implementation of StaffUserRepository
+type staffUserRepository struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+}
+
+// NewStaffUserRepository creates a new staff user repository
+func NewStaffUserRepository(db *sql.DB, queryTimeout time.Duration) StaffUserRepository {
+	return &staffUserRepository{db: db, queryTimeout: queryTimeout}
+}
+
+// Create inserts a staff user and returns its ID
+func (r *staffUserRepository) Create(ctx context.Context, user *models.StaffUser) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var id int64
+
+	query := `
+		INSERT INTO staff_users (email, name, password_hash, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id
+	`
+
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, query, user.Email, user.Name, user.PasswordHash, user.IsActive).Scan(&id)
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating staff user", slog.Any("error", err))
+		return 0, fmt.Errorf("failed to create staff user: %w", apperrors.FromDB(err))
+	}
+
+	return id, nil
+}
+
+// GetByID retrieves a staff user
+func (r *staffUserRepository) GetByID(ctx context.Context, id int64) (*models.StaffUser, error) {
+	return r.get(ctx, `id = $1`, id)
+}
+
+// GetByEmail retrieves a staff user by email
+func (r *staffUserRepository) GetByEmail(ctx context.Context, email string) (*models.StaffUser, error) {
+	return r.get(ctx, `lower(email) = $1`, email)
+}
+
+func (r *staffUserRepository) get(ctx context.Context, where string, arg any) (*models.StaffUser, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + staffUserColumns + `
+		FROM staff_users
+		WHERE ` + where
+
+	user, err := scanStaffUser(database.Conn(ctx, r.db).QueryRowContext(ctx, query, arg))
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NotFound("staff user")
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching staff user", slog.Any("error", err))
+		return nil, fmt.Errorf("failed to fetch staff user: %w", apperrors.FromDB(err))
+	}
+
+	return user, nil
+}
+
+// UpdatePasswordHash replaces a staff user's password hash
+func (r *staffUserRepository) UpdatePasswordHash(ctx context.Context, id int64, hash string) error {
+	return r.exec(ctx, "updating staff user password", `UPDATE staff_users SET password_hash = $2 WHERE id = $1`, id, hash)
+}
+
+// RecordLogin sets a staff user's last_login_at to now
+func (r *staffUserRepository) RecordLogin(ctx context.Context, id int64) error {
+	return r.exec(ctx, "recording staff user login", `UPDATE staff_users SET last_login_at = CURRENT_TIMESTAMP WHERE id = $1`, id)
+}
+
+// exec runs an update of one staff user; action names it in errors
+func (r *staffUserRepository) exec(ctx context.Context, action, query string, args ...any) error {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := database.Conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error("error "+action, slog.Any("error", err))
+		return fmt.Errorf("failed %s: %w", action, apperrors.FromDB(err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.NotFound("staff user")
+	}
+
+	return nil
+}
+
+// scanStaffUser scans a row selected with staffUserColumns
+func scanStaffUser(row rowScanner) (*models.StaffUser, error) {
+	var user models.StaffUser
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.Name,
+		&user.PasswordHash,
+		&user.IsActive,
+		&user.LastLoginAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"ecom/internal/apperrors"
+	"ecom/internal/database"
+	"ecom/internal/models"
+)
+
+// userRepository is the Postgres implementation of UserRepository
+type userRepository struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *sql.DB, queryTimeout time.Duration) UserRepository {
+	return &userRepository{db: db, queryTimeout: queryTimeout}
+}
+
+// GetByID retrieves a user by ID
+func (r *userRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT id, email, first_name, last_name, created_at, updated_at
+	          FROM users WHERE id = $1`
+
+	user := &models.User{}
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&user.ID,
+		&user.Email,
+		&user.FirstName,
+		&user.LastName,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("user")
+		}
+		return nil, apperrors.FromDB(err)
+	}
+
+	return user, nil
+}
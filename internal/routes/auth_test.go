@@ -0,0 +1,212 @@
+package routes_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ecom/internal/auth"
+	"ecom/internal/database"
+	"ecom/internal/dto"
+	"ecom/internal/repositories"
+	"ecom/internal/routes"
+	"ecom/internal/services"
+	"ecom/internal/testutil/pgtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// call sends a JSON request with an optional Authorization header value
+func call(router http.Handler, method, path, authorization, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// tokensFrom decodes the token pair in a successful response
+func tokensFrom(t *testing.T, rec *httptest.ResponseRecorder) dto.TokenResponse {
+	t.Helper()
+	var envelope struct {
+		Data dto.TokenResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode tokens: %v", err)
+	}
+	if envelope.Data.AccessToken == "" || envelope.Data.RefreshToken == "" || envelope.Data.TokenType != "Bearer" {
+		t.Fatalf("incomplete tokens: %s", rec.Body)
+	}
+	return envelope.Data
+}
+
+func TestStaffOperationsRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// Handlers are not wired: every request below is refused before them
+	routes.SetupRoutes(router, &routes.Handlers{}, routes.Options{Tokens: newTokens(t)})
+
+	body := `{"name":"Books","slug":"books"}`
+	tests := []struct {
+		name, authorization string
+		status              int
+	}{
+		{"anonymous", "", http.StatusUnauthorized},
+		{"customer", bearer(t, auth.Principal{Kind: auth.KindCustomer, ID: 7, Permissions: []auth.Permission{auth.PermOwnOrders}}), http.StatusForbidden},
+		{"staff without the permission", bearer(t, auth.Principal{Kind: auth.KindStaff, ID: 2, Permissions: []auth.Permission{auth.PermStockAdjust}}), http.StatusForbidden},
+		{"malformed header", "Token abc", http.StatusUnauthorized},
+		{"invalid token", "Bearer not.a.jwt", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := call(router, http.MethodPost, "/api/v1/categories", tt.authorization, body)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 responses must carry WWW-Authenticate")
+			}
+		})
+	}
+
+	// Tokens signed with another key are rejected, even for reads
+	cfg := testAuth
+	cfg.SigningKeys = nil
+	other, err := auth.NewTokens(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, _, err := other.Issue(auth.Principal{Kind: auth.KindStaff, ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := call(router, http.MethodGet, "/health/live", "Bearer "+forged, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("forged token: status %d", rec.Code)
+	}
+}
+
+func TestAuthFlow(t *testing.T) {
+	router := newTestRouter(t)
+	v := loadSpec(t)
+	check := func(name string, rec *httptest.ResponseRecorder, method, path, body string, want int) {
+		t.Helper()
+		if rec.Code != want {
+			t.Fatalf("%s: status = %d, want %d: %s", name, rec.Code, want, rec.Body)
+		}
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if err := v.ValidateResponse(req, rec.Code, rec.Header(), rec.Body.Bytes()); err != nil {
+			t.Errorf("%s: response violates the spec: %v", name, err)
+		}
+	}
+	post := func(name, path, body string, want int) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := call(router, http.MethodPost, path, "", body)
+		check(name, rec, http.MethodPost, path, body, want)
+		return rec
+	}
+
+	register := `{"email":"Jane@Example.com","password":"correct horse","first_name":"Jane","last_name":"Doe"}`
+	post("register", "/api/v1/auth/register", register, http.StatusCreated)
+	post("register twice", "/api/v1/auth/register", strings.Replace(register, "Jane@", "jane@", 1), http.StatusConflict)
+	post("short password", "/api/v1/auth/register", strings.Replace(register, "correct horse", "short", 1), http.StatusBadRequest)
+
+	post("wrong password", "/api/v1/auth/login", `{"email":"jane@example.com","password":"incorrect horse"}`, http.StatusUnauthorized)
+	post("unknown email", "/api/v1/auth/login", `{"email":"joe@example.com","password":"correct horse"}`, http.StatusUnauthorized)
+	post("customer as staff", "/api/v1/auth/staff/login", `{"email":"jane@example.com","password":"correct horse"}`, http.StatusUnauthorized)
+	login := tokensFrom(t, post("login", "/api/v1/auth/login", `{"email":"JANE@example.com","password":"correct horse"}`, http.StatusOK))
+
+	rec := call(router, http.MethodGet, "/api/v1/auth/me", "Bearer "+login.AccessToken, "")
+	check("me", rec, http.MethodGet, "/api/v1/auth/me", "", http.StatusOK)
+	if !strings.Contains(rec.Body.String(), `"email":"jane@example.com"`) || !strings.Contains(rec.Body.String(), `"kind":"customer"`) ||
+		!strings.Contains(rec.Body.String(), `"permissions":["addresses:own","orders:own","wishlists:own"]`) {
+		t.Errorf("me: %s", rec.Body)
+	}
+	check("me anonymous", call(router, http.MethodGet, "/api/v1/auth/me", "", ""), http.MethodGet, "/api/v1/auth/me", "", http.StatusUnauthorized)
+
+	// Each refresh token is exchanged once; reusing one revokes the family,
+	// including the token it was exchanged for
+	refresh := func(token string) string { return `{"refresh_token":"` + token + `"}` }
+	next := tokensFrom(t, post("refresh", "/api/v1/auth/refresh", refresh(login.RefreshToken), http.StatusOK))
+	if next.RefreshToken == login.RefreshToken {
+		t.Fatal("refresh must rotate the refresh token")
+	}
+	post("reuse", "/api/v1/auth/refresh", refresh(login.RefreshToken), http.StatusUnauthorized)
+	post("after reuse", "/api/v1/auth/refresh", refresh(next.RefreshToken), http.StatusUnauthorized)
+	post("unknown refresh token", "/api/v1/auth/refresh", refresh("unknown"), http.StatusUnauthorized)
+
+	// Logging out ends the login; logging out again is harmless
+	login = tokensFrom(t, post("login again", "/api/v1/auth/login", `{"email":"jane@example.com","password":"correct horse"}`, http.StatusOK))
+	post("logout", "/api/v1/auth/logout", refresh(login.RefreshToken), http.StatusNoContent)
+	post("refresh after logout", "/api/v1/auth/refresh", refresh(login.RefreshToken), http.StatusUnauthorized)
+	post("logout twice", "/api/v1/auth/logout", refresh(login.RefreshToken), http.StatusNoContent)
+}
+
+func TestStaffRoles(t *testing.T) {
+	db := pgtest.NewDB(t)
+	router := newTestRouterOn(t, db)
+	authService := services.NewAuthService(
+		database.NewTxManager(db, nil),
+		repositories.NewCustomerRepository(db, 0),
+		repositories.NewStaffUserRepository(db, 0),
+		repositories.NewRefreshTokenRepository(db, 0),
+		repositories.NewRoleRepository(db, 0),
+		newTokens(t),
+	)
+	login := func(email, role string) string {
+		t.Helper()
+		if _, err := authService.CreateStaffUser(context.Background(), email, role, "correct horse", []string{role}); err != nil {
+			t.Fatal(err)
+		}
+		body := `{"email":"` + email + `","password":"correct horse"}`
+		return "Bearer " + tokensFrom(t, call(router, http.MethodPost, "/api/v1/auth/staff/login", "", body)).AccessToken
+	}
+	catalog := login("catalog@example.com", auth.RoleCatalogManager)
+	warehouse := login("warehouse@example.com", auth.RoleWarehouse)
+
+	for _, roles := range [][]string{{"unknown"}, {auth.RoleCustomer}} {
+		if _, err := authService.CreateStaffUser(context.Background(), "x@example.com", "X", "correct horse", roles); err == nil {
+			t.Errorf("roles %v: no error", roles)
+		}
+	}
+
+	// Catalog managers maintain products, with their opening stock; later
+	// stock changes are for the warehouse, which cannot change anything else
+	if rec := call(router, http.MethodPost, "/api/v1/categories", catalog, `{"name":"Books","slug":"books"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("create category: %d %s", rec.Code, rec.Body)
+	}
+	product := `{"sku":"BOOK-001","name":"Go in Action","slug":"go-in-action","category_id":1,"status":"active","price":39.99,"stock_quantity":5}`
+	tests := []struct {
+		name, authorization, method, path, body string
+		status                                  int
+	}{
+		{"catalog creates", catalog, http.MethodPost, "/api/v1/products", product, http.StatusCreated},
+		{"catalog adjusts stock", catalog, http.MethodPut, "/api/v1/products/1", `{"stock_quantity":3}`, http.StatusForbidden},
+		{"catalog updates", catalog, http.MethodPut, "/api/v1/products/1", `{"price":34.99}`, http.StatusOK},
+		{"warehouse adjusts stock", warehouse, http.MethodPut, "/api/v1/products/1", `{"stock_quantity":3}`, http.StatusOK},
+		{"warehouse updates", warehouse, http.MethodPut, "/api/v1/products/1", `{"price":1,"stock_quantity":4}`, http.StatusForbidden},
+		{"warehouse deletes", warehouse, http.MethodDelete, "/api/v1/products/1", "", http.StatusForbidden},
+		{"warehouse creates categories", warehouse, http.MethodPost, "/api/v1/categories", `{"name":"Games","slug":"games"}`, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if rec := call(router, tt.method, tt.path, tt.authorization, tt.body); rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, rec.Code, tt.status, rec.Body)
+		}
+	}
+
+	rec := call(router, http.MethodGet, "/api/v1/auth/me", warehouse, "")
+	if !strings.Contains(rec.Body.String(), `"permissions":["orders:read","stock:adjust"]`) {
+		t.Errorf("me: %s", rec.Body)
+	}
+}
@@ -0,0 +1,83 @@
+package routes_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ecom/docs"
+	"ecom/internal/openapi"
+	"ecom/internal/routes"
+
+	"github.com/gin-gonic/gin"
+)
+
+func loadSpec(t *testing.T) *openapi.Validator {
+	t.Helper()
+	v, err := openapi.Parse(docs.Spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// routeTable registers the routes without wiring their handlers
+func routeTable() gin.RoutesInfo {
+	router := gin.New()
+	routes.SetupRoutes(router, &routes.Handlers{}, routes.Options{})
+	return router.Routes()
+}
+
+func TestSpecIsUpToDate(t *testing.T) {
+	spec, err := openapi.Generate(routeTable(), "../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(spec, docs.Spec) {
+		t.Error("docs/swagger.yaml is stale; run go run ./cmd/openapi")
+	}
+}
+
+func TestRoutesAreDocumented(t *testing.T) {
+	v := loadSpec(t)
+	for _, route := range v.UndocumentedRoutes(routeTable()) {
+		t.Errorf("%s is not documented in docs/swagger.yaml", route)
+	}
+}
+
+func TestCategoryEndpointsMatchSpec(t *testing.T) {
+	checkSteps(t, loadSpec(t), newTestRouter(t), categorySteps)
+}
+
+func TestProductEndpointsMatchSpec(t *testing.T) {
+	checkSteps(t, loadSpec(t), newTestRouter(t), productSteps)
+}
+
+// checkSteps validates every response against the spec, and the requests
+// the API accepted. Rejected requests are meant to break the contract.
+func checkSteps(t *testing.T, v *openapi.Validator, router http.Handler, steps []step) {
+	staff := bearer(t, catalogStaff)
+	for _, s := range steps {
+		newRequest := func() *http.Request {
+			req := httptest.NewRequest(s.method, s.path, bytes.NewReader([]byte(s.body)))
+			if s.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			req.Header.Set("Authorization", staff)
+			return req
+		}
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, newRequest())
+
+		if rec.Code < 400 {
+			if err := v.ValidateRequest(newRequest()); err != nil {
+				t.Errorf("%s: request violates the spec: %v", s.name, err)
+			}
+		}
+		if err := v.ValidateResponse(newRequest(), rec.Code, rec.Header(), rec.Body.Bytes()); err != nil {
+			t.Errorf("%s: %d response violates the spec: %v", s.name, rec.Code, err)
+		}
+	}
+}
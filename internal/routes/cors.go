@@ -0,0 +1,87 @@
+package routes
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"ecom/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// routeSet holds routes by method and template, e.g. "DELETE
+// /api/v1/products/:id"
+type routeSet map[string]bool
+
+func (s routeSet) add(method, route string) { s[method+" "+route] = true }
+
+func (s routeSet) has(method, route string) bool { return s[method+" "+route] }
+
+// staffRoutes registers staff operations on a group, behind
+// auth.RequirePermission, and records them in routes. Each route declares
+// its permissions with require.
+type staffRoutes struct {
+	*gin.RouterGroup
+	routes      routeSet
+	permissions []auth.Permission
+}
+
+// require returns g registering routes that callers holding any of perms
+// may call. Services check finer rules, such as which fields need which
+// permission.
+func (g staffRoutes) require(perms ...auth.Permission) staffRoutes {
+	g.permissions = perms
+	return g
+}
+
+func (g staffRoutes) Handle(method, path string, handlers ...gin.HandlerFunc) {
+	if len(g.permissions) == 0 {
+		panic("routes: staff route " + method + " " + g.BasePath() + path + " declares no permission")
+	}
+	g.routes.add(method, g.BasePath()+path)
+	g.RouterGroup.Handle(method, path, append([]gin.HandlerFunc{auth.RequirePermission(g.permissions...)}, handlers...)...)
+}
+
+func (g staffRoutes) POST(path string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPost, path, handlers...)
+}
+
+func (g staffRoutes) PUT(path string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPut, path, handlers...)
+}
+
+func (g staffRoutes) DELETE(path string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodDelete, path, handlers...)
+}
+
+// registerOptions adds an OPTIONS route for every path, so preflight
+// requests reach the CORS middleware with their route. OPTIONS requests that
+// are not preflights are answered with the path's methods in Allow.
+func registerOptions(router *gin.Engine) {
+	methods := make(map[string][]string)
+	for _, route := range router.Routes() {
+		methods[route.Path] = append(methods[route.Path], route.Method)
+	}
+	for path, allowed := range methods {
+		if contains(allowed, http.MethodOptions) {
+			continue
+		}
+		allowed = append(allowed, http.MethodOptions)
+		sort.Strings(allowed)
+		allow := strings.Join(allowed, ", ")
+		router.OPTIONS(path, func(c *gin.Context) {
+			c.Header("Allow", allow)
+			c.Status(http.StatusNoContent)
+		})
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
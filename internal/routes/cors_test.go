@@ -0,0 +1,125 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ecom/internal/handlers"
+	"ecom/internal/middleware"
+	"ecom/internal/routes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newCORSRouter serves the routes under a storefront and an admin policy.
+// Only the liveness handler is wired: preflights never reach handlers.
+func newCORSRouter() http.Handler {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes.SetupRoutes(router, &routes.Handlers{Health: handlers.NewHealthHandler(nil, nil)}, routes.Options{
+		CORS: &middleware.CORSPolicy{
+			AllowedOrigins: []string{"https://shop.example.com", "https://*.preview.example.com"},
+			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE"},
+			AllowedHeaders: []string{"Content-Type", "Authorization"},
+			ExposedHeaders: []string{"X-Request-ID"},
+		},
+		AdminCORS: &middleware.CORSPolicy{
+			AllowedOrigins:   []string{"https://admin.example.com"},
+			AllowedMethods:   []string{"POST", "PUT", "DELETE"},
+			AllowedHeaders:   []string{"Content-Type"},
+			AllowCredentials: true,
+		},
+	})
+	return router
+}
+
+func serve(router http.Handler, method, path string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func preflight(router http.Handler, path, origin, method string) *httptest.ResponseRecorder {
+	return serve(router, http.MethodOptions, path, map[string]string{
+		"Origin":                         origin,
+		"Access-Control-Request-Method":  method,
+		"Access-Control-Request-Headers": "content-type",
+	})
+}
+
+func TestCORSPreflightUsesThePolicyOfTheRequestedRoute(t *testing.T) {
+	router := newCORSRouter()
+
+	tests := []struct {
+		name, path, origin, method string
+		status                     int
+		credentials                string
+	}{
+		{"storefront read", "/api/v1/products/7", "https://shop.example.com", "GET", http.StatusNoContent, ""},
+		{"preview subdomain", "/api/v1/products", "https://pr-12.preview.example.com", "GET", http.StatusNoContent, ""},
+		{"storefront cannot write the catalog", "/api/v1/products", "https://shop.example.com", "POST", http.StatusForbidden, ""},
+		{"admin writes the catalog", "/api/v1/products", "https://admin.example.com", "POST", http.StatusNoContent, "true"},
+		{"admin policy is not used for reads", "/api/v1/products", "https://admin.example.com", "GET", http.StatusForbidden, ""},
+		{"bare domain is not a subdomain", "/api/v1/products", "https://preview.example.com", "GET", http.StatusForbidden, ""},
+		{"lookalike domain", "/api/v1/products", "https://shop.example.com.evil.test", "GET", http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := preflight(router, tt.path, tt.origin, tt.method)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			allowed := rec.Header().Get("Access-Control-Allow-Origin")
+			if tt.status == http.StatusNoContent && allowed != tt.origin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want the origin reflected", allowed)
+			}
+			if tt.status != http.StatusNoContent && allowed != "" {
+				t.Errorf("rejected preflight allowed origin %q", allowed)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != tt.credentials {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want %q", got, tt.credentials)
+			}
+			if got := rec.Header().Values("Vary"); len(got) == 0 || got[0] != "Origin" {
+				t.Errorf("Vary = %v, want Origin first", got)
+			}
+		})
+	}
+}
+
+func TestCORSActualRequests(t *testing.T) {
+	router := newCORSRouter()
+
+	rec := serve(router, http.MethodGet, "/health/live", map[string]string{"Origin": "https://shop.example.com"})
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://shop.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); got != "X-Request-ID" {
+		t.Errorf("Access-Control-Expose-Headers = %q", got)
+	}
+
+	rec = serve(router, http.MethodGet, "/health/live", map[string]string{"Origin": "https://evil.test"})
+	if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("disallowed origin: status %d, allowed %q", rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if rec.Header().Get("Vary") != "Origin" {
+		t.Error("responses must vary by Origin even when it is not allowed")
+	}
+}
+
+func TestOptionsWithoutCORSIsNotAPreflight(t *testing.T) {
+	rec := serve(newCORSRouter(), http.MethodOptions, "/api/v1/categories/3", nil)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if got := rec.Header().Get("Allow"); got != "DELETE, GET, OPTIONS, PUT" {
+		t.Errorf("Allow = %q", got)
+	}
+	if rec.Header().Get("Access-Control-Allow-Methods") != "" {
+		t.Error("plain OPTIONS requests must not get CORS headers")
+	}
+}
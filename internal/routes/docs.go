@@ -0,0 +1,139 @@
+package routes
+
+import (
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// DocsOptions configure the documentation routes
+type DocsOptions struct {
+	// Title is the page title of Swagger UI and ReDoc
+	Title string
+	// SpecPath is the URL path the OpenAPI spec is served at
+	SpecPath string
+	// Spec, when set, is served as the OpenAPI spec instead of reading
+	// docs/swagger.yaml from disk
+	Spec []byte
+	// ReDoc holds redoc.standalone.js; the ReDoc view is left out without it
+	ReDoc fs.FS
+}
+
+// redocBundle is the ReDoc script vendored in docs/redoc
+const redocBundle = "redoc.standalone.js"
+
+// SetupDocs serves the OpenAPI spec, Swagger UI under /swagger/ and ReDoc
+// under /redoc. All assets are embedded in the binary, so the pages work
+// without internet access.
+func SetupDocs(router *gin.Engine, opts DocsOptions) {
+	page := docsPage{Title: opts.Title, SpecURL: opts.SpecPath}
+
+	// Serve the spec
+	router.GET(opts.SpecPath, func(c *gin.Context) {
+		if opts.Spec != nil {
+			c.Data(http.StatusOK, "application/yaml; charset=utf-8", opts.Spec)
+			return
+		}
+		c.File("docs/swagger.yaml")
+	})
+
+	// Serve Swagger UI: the page itself and the swagger-ui-dist assets
+	assets := http.StripPrefix("/swagger", http.FileServer(http.FS(swaggerFiles.FS)))
+	router.GET("/swagger/*filepath", func(c *gin.Context) {
+		switch c.Param("filepath") {
+		case "/", "/index.html":
+			page.render(c, swaggerUITemplate)
+		default:
+			assets.ServeHTTP(c.Writer, c.Request)
+		}
+	})
+
+	// Serve ReDoc, when its bundle is vendored
+	if opts.ReDoc == nil {
+		return
+	}
+	if _, err := fs.Stat(opts.ReDoc, redocBundle); err != nil {
+		slog.Warn("ReDoc disabled: the bundle is not vendored (run go generate ./docs)", slog.String("file", redocBundle))
+		return
+	}
+	router.GET("/redoc", func(c *gin.Context) {
+		page.render(c, redocTemplate)
+	})
+	router.GET("/redoc/"+redocBundle, func(c *gin.Context) {
+		c.FileFromFS(redocBundle, http.FS(opts.ReDoc))
+	})
+}
+
+// docsPage is the data of the documentation page templates
+type docsPage struct {
+	Title   string
+	SpecURL string
+}
+
+func (p docsPage) render(c *gin.Context, tmpl *template.Template) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := tmpl.Execute(c.Writer, p); err != nil {
+		c.Error(err)
+	}
+}
+
+var swaggerUITemplate = template.Must(template.New("swagger").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="UTF-8">
+	<title>{{.Title}}</title>
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<link rel="stylesheet" href="/swagger/swagger-ui.css">
+	<link rel="icon" type="image/png" href="/swagger/favicon-32x32.png" sizes="32x32">
+	<style>
+		html { box-sizing: border-box; overflow-y: scroll; }
+		*, *:before, *:after { box-sizing: inherit; }
+		body { margin: 0; padding: 0; }
+	</style>
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="/swagger/swagger-ui-bundle.js" charset="UTF-8"></script>
+	<script src="/swagger/swagger-ui-standalone-preset.js" charset="UTF-8"></script>
+	<script>
+		window.onload = function() {
+			window.ui = SwaggerUIBundle({
+				url: {{.SpecURL}},
+				dom_id: '#swagger-ui',
+				deepLinking: true,
+				presets: [
+					SwaggerUIBundle.presets.apis,
+					SwaggerUIStandalonePreset
+				],
+				plugins: [
+					SwaggerUIBundle.plugins.DownloadUrl
+				],
+				layout: "StandaloneLayout"
+			});
+		};
+	</script>
+</body>
+</html>
+`))
+
+var redocTemplate = template.Must(template.New("redoc").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="UTF-8">
+	<title>{{.Title}}</title>
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<style>
+		body { margin: 0; padding: 0; }
+	</style>
+</head>
+<body>
+	<redoc spec-url="{{.SpecURL}}"></redoc>
+	<script src="/redoc/redoc.standalone.js"></script>
+</body>
+</html>
+`))
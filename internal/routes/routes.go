@@ -1,60 +1,187 @@
 package routes
 
 import (
+	"log"
+	"log/slog"
+	"time"
+
+	"ecom/internal/auth"
 	"ecom/internal/handlers"
+	"ecom/internal/metrics"
 	"ecom/internal/middleware"
+	"ecom/internal/openapi"
+	"ecom/internal/tracing"
+	"ecom/internal/validation"
 
 	"github.com/gin-gonic/gin"
 )
 
-// SetupRoutes configures all application routes using Gin
-func SetupRoutes(router *gin.Engine) {
-	// Setup Swagger documentation routes
-	SetupSwagger(router)
+// Handlers are the HTTP handlers the routes are bound to. They are built by
+// the composition root (cmd/server) with their dependencies already wired.
+type Handlers struct {
+	Health   *handlers.HealthHandler
+	Category *handlers.CategoryHandler
+	Product  *handlers.ProductHandler
+	Auth     *handlers.AuthHandler
+}
 
-	// Apply global middleware
-	router.Use(middleware.ErrorHandler())
-	router.Use(middleware.RequestLogger())
-	router.Use(middleware.CORSMiddleware())
+// Options tune the middleware applied to the routes
+type Options struct {
+	// Logger is the base of the request-scoped loggers; nil uses
+	// slog.Default()
+	Logger *slog.Logger
+	// RequestTimeout bounds each API request; 0 disables it
+	RequestTimeout time.Duration
+	// OpenAPIValidator, when set, logs requests and responses that do not
+	// match docs/swagger.yaml
+	OpenAPIValidator *openapi.Validator
+	// Metrics, when set, records request metrics and serves them at
+	// MetricsPath (default /metrics)
+	Metrics     *metrics.Metrics
+	MetricsPath string
+	// Tracing starts a span per request and continues incoming traces
+	Tracing bool
+	// Docs, when set, serves the spec, Swagger UI and ReDoc; nil leaves the
+	// documentation routes out entirely
+	Docs *DocsOptions
+	// CORS is the policy for cross-origin browser requests, and AdminCORS
+	// the one for staff operations (catalog writes). A nil policy allows no
+	// cross-origin access; with both nil, CORS headers are left out
+	// entirely. The server always passes both (see corsPolicy in
+	// cmd/server), so an unset AdminCORS allows no origins rather than
+	// inheriting CORS.
+	CORS      *middleware.CORSPolicy
+	AdminCORS *middleware.CORSPolicy
+	// Tokens verifies the access tokens of authenticated requests; with nil
+	// every request is anonymous and operations that need a permission are
+	// refused
+	Tokens *auth.Tokens
+}
+
+// SetupRoutes configures all application routes using Gin.
+func SetupRoutes(router *gin.Engine, h *Handlers, opts Options) {
+	// Translated field-level messages and custom rules for request binding
+	if err := validation.Setup(); err != nil {
+		log.Fatalf("Failed to set up request validation: %v", err)
+	}
+
+	// Setup documentation routes
+	if opts.Docs != nil {
+		SetupDocs(router, *opts.Docs)
+	}
+
+	// Serve metrics; registered before the middleware so scrapes are not
+	// logged or measured
+	if opts.Metrics != nil {
+		path := opts.MetricsPath
+		if path == "" {
+			path = "/metrics"
+		}
+		router.GET(path, gin.WrapH(opts.Metrics.Handler()))
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	// Apply global middleware (the request ID and trace come first so every
+	// log line carries them; the logger wraps ErrorHandler so it sees the
+	// status of mapped errors)
 	router.Use(middleware.RequestIDMiddleware())
+	if opts.Tracing {
+		router.Use(tracing.Middleware())
+	}
+	router.Use(middleware.RequestLogger(logger))
+	if opts.Metrics != nil {
+		router.Use(opts.Metrics.Middleware())
+	}
+	if opts.OpenAPIValidator != nil {
+		// Outside ErrorHandler, so it sees the mapped error responses
+		router.Use(opts.OpenAPIValidator.Middleware())
+	}
+	router.Use(middleware.ErrorHandler())
+	staff := make(routeSet)
+	cors := opts.CORS != nil || opts.AdminCORS != nil
+	if cors {
+		router.Use(middleware.CORS(func(method, route string) *middleware.CORSPolicy {
+			if staff.has(method, route) {
+				return opts.AdminCORS
+			}
+			return opts.CORS
+		}))
+	}
+	// After CORS, so preflights (which carry no credentials) are answered
+	router.Use(auth.Middleware(opts.Tokens))
+
+	// Liveness probe (process only); /health and /api/health are kept for
+	// existing monitors
+	router.GET("/health/live", h.Health.Live)
+	router.GET("/health", h.Health.Live)
+	router.GET("/api/health", h.Health.Live)
 
-	// Health check endpoint
-	router.GET("/health", healthCheck)
-	router.GET("/api/health", healthCheck)
+	// Readiness probe (database reachable and migrations current)
+	router.GET("/health/ready", h.Health.Ready)
 
-	// API v1 routes
+	// API v1 routes (the timeout runs inside ErrorHandler, so a 504 is
+	// written before mapped handler errors are considered)
 	v1 := router.Group("/api/v1")
+	v1.Use(middleware.TimeoutMiddleware(opts.RequestTimeout))
+	// Staff operations, restricted to callers holding the permissions each
+	// declares and recorded for the admin CORS policy
+	admin := staffRoutes{RouterGroup: v1, routes: staff}
+	catalog := admin.require(auth.PermCatalogWrite)
+	// Stock quantities are set through product updates; ProductService
+	// checks which of the two permissions the changed fields need
+	products := admin.require(auth.PermCatalogWrite, auth.PermStockAdjust)
 	{
+		// Auth routes
+		authHandler := h.Auth
+		{
+			v1.POST("/auth/register", authHandler.Register)
+			v1.POST("/auth/login", authHandler.Login)
+			v1.POST("/auth/staff/login", authHandler.StaffLogin)
+			v1.POST("/auth/refresh", authHandler.Refresh)
+			v1.POST("/auth/logout", authHandler.Logout)
+			v1.GET("/auth/me", auth.RequireAuth(), authHandler.Me)
+		}
+
 		// Category routes
-		categoryHandler := handlers.NewCategoryHandler()
+		categoryHandler := h.Category
 		{
-			v1.POST("/categories", categoryHandler.CreateCategory)
+			catalog.POST("/categories", categoryHandler.CreateCategory)
 			v1.GET("/categories", categoryHandler.GetAllCategories)
 			v1.GET("/categories/:id", categoryHandler.GetCategory)
-			v1.PUT("/categories/:id", categoryHandler.UpdateCategory)
-			v1.DELETE("/categories/:id", categoryHandler.DeleteCategory)
+			catalog.PUT("/categories/:id", categoryHandler.UpdateCategory)
+			catalog.DELETE("/categories/:id", categoryHandler.DeleteCategory)
 			v1.GET("/categories/:id/products", categoryHandler.GetCategoryProducts)
 		}
 
 		// Product routes
-		productHandler := handlers.NewProductHandler()
+		productHandler := h.Product
 		{
-			v1.POST("/products", productHandler.CreateProduct)
+			catalog.POST("/products", productHandler.CreateProduct)
 			v1.GET("/products", productHandler.GetAllProducts)
 			v1.GET("/products/:id", productHandler.GetProduct)
-			v1.PUT("/products/:id", productHandler.UpdateProduct)
-			v1.DELETE("/products/:id", productHandler.DeleteProduct)
+			products.PUT("/products/:id", productHandler.UpdateProduct)
+			catalog.DELETE("/products/:id", productHandler.DeleteProduct)
 			v1.GET("/products/category/:category_id", productHandler.GetProductsByCategoryID)
 		}
 
-		// Customer routes (placeholder for Phase 4)
+		// Customer routes (placeholder for Phase 4). Customers may only
+		// reach their own account, addresses and wishlists: the services
+		// that serve them must check auth.AuthorizeOwner with
+		// PermOwnAddresses or PermOwnWishlists, which staff pass with
+		// PermCustomersRead. Until then those permissions are not enforced.
 		// v1.POST("/customers", customerHandler.CreateCustomer)
 		// v1.GET("/customers", customerHandler.GetAllCustomers)
 		// v1.GET("/customers/:id", customerHandler.GetCustomer)
 		// v1.PUT("/customers/:id", customerHandler.UpdateCustomer)
 		// v1.DELETE("/customers/:id", customerHandler.DeleteCustomer)
 
-		// Order routes (placeholder for Phase 5)
+		// Order routes (placeholder for Phase 5). The order service must
+		// check auth.AuthorizeOwner with PermOwnOrders and PermOrdersRead;
+		// refunds are admin.require(auth.PermOrdersRefund).
 		// v1.POST("/orders", orderHandler.CreateOrder)
 		// v1.GET("/orders", orderHandler.GetAllOrders)
 		// v1.GET("/orders/:id", orderHandler.GetOrder)
@@ -67,13 +194,10 @@ func SetupRoutes(router *gin.Engine) {
 		// v1.GET("/payments/:id", paymentHandler.GetPayment)
 		// v1.PUT("/payments/:id", paymentHandler.UpdatePayment)
 	}
-}
 
-// healthCheck is a simple health check endpoint
-func healthCheck(c *gin.Context) {
-	c.JSON(200, gin.H{
-		"status":  "OK",
-		"message": "Server is running",
-	})
+	// Preflight requests need a route for CORS to pick the policy of the
+	// route they ask about
+	if cors {
+		registerOptions(router)
+	}
 }
-
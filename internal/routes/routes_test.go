@@ -0,0 +1,230 @@
+package routes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"ecom/internal/auth"
+	"ecom/internal/config"
+	"ecom/internal/database"
+	"ecom/internal/handlers"
+	"ecom/internal/repositories"
+	"ecom/internal/requestid"
+	"ecom/internal/routes"
+	"ecom/internal/services"
+	"ecom/internal/testutil/golden"
+	"ecom/internal/testutil/pgtest"
+	"ecom/migrations"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Exit(pgtest.RunMain(m))
+}
+
+// step is one request of an endpoint scenario. Steps run in order against
+// the same database, so later steps see the effects of earlier ones.
+type step struct {
+	name   string
+	method string
+	path   string
+	body   string
+}
+
+var categorySteps = []step{
+	{"create", http.MethodPost, "/api/v1/categories", `{"name":"Electronics","slug":"electronics","description":"Devices and gadgets","is_active":true,"sort_order":1}`},
+	{"create_child", http.MethodPost, "/api/v1/categories", `{"name":"Laptops","slug":"laptops","parent_id":1,"image_url":"https://example.com/laptops.png","is_active":true,"sort_order":2}`},
+	{"create_invalid", http.MethodPost, "/api/v1/categories", `{"name":"","slug":"Not A Slug"}`},
+	{"create_malformed", http.MethodPost, "/api/v1/categories", `{"name":`},
+	{"create_duplicate_slug", http.MethodPost, "/api/v1/categories", `{"name":"Gadgets","slug":"electronics"}`},
+	{"create_unknown_parent", http.MethodPost, "/api/v1/categories", `{"name":"Orphans","slug":"orphans","parent_id":999}`},
+	{"list", http.MethodGet, "/api/v1/categories", ""},
+	{"list_page_2", http.MethodGet, "/api/v1/categories?page=2&limit=1", ""},
+	{"get", http.MethodGet, "/api/v1/categories/1", ""},
+	{"get_not_found", http.MethodGet, "/api/v1/categories/999", ""},
+	{"get_invalid_id", http.MethodGet, "/api/v1/categories/abc", ""},
+	{"update", http.MethodPut, "/api/v1/categories/1", `{"name":"Consumer Electronics","sort_order":5}`},
+	{"update_duplicate_slug", http.MethodPut, "/api/v1/categories/1", `{"slug":"laptops"}`},
+	{"update_not_found", http.MethodPut, "/api/v1/categories/999", `{"name":"Missing"}`},
+	{"products", http.MethodGet, "/api/v1/categories/1/products", ""},
+	{"products_not_found", http.MethodGet, "/api/v1/categories/999/products", ""},
+	{"delete", http.MethodDelete, "/api/v1/categories/2", ""},
+	{"delete_not_found", http.MethodDelete, "/api/v1/categories/2", ""},
+	{"get_deleted", http.MethodGet, "/api/v1/categories/2", ""},
+}
+
+var productSteps = []step{
+	{"create_category", http.MethodPost, "/api/v1/categories", `{"name":"Books","slug":"books","is_active":true}`},
+	{"create", http.MethodPost, "/api/v1/products", `{"sku":"BOOK-001","name":"Go in Action","slug":"go-in-action","description":"An introduction to Go","short_description":"Go basics","category_id":1,"status":"active","price":39.99,"compare_at_price":49.99,"stock_quantity":25}`},
+	{"create_second", http.MethodPost, "/api/v1/products", `{"sku":"BOOK-002","name":"The Go Programming Language","slug":"the-go-programming-language","category_id":1,"status":"active","price":44.5,"stock_quantity":10}`},
+	{"create_invalid", http.MethodPost, "/api/v1/products", `{"sku":"bad sku!","name":"","slug":"bad-slug","status":"unknown","price":-1}`},
+	{"create_duplicate_sku", http.MethodPost, "/api/v1/products", `{"sku":"BOOK-001","name":"Go in Action, 2nd Edition","slug":"go-in-action-2","category_id":1,"status":"active","price":39.99,"stock_quantity":5}`},
+	{"create_unknown_category", http.MethodPost, "/api/v1/products", `{"sku":"BOOK-003","name":"Lost","slug":"lost","category_id":999,"status":"active","price":9.99,"stock_quantity":1}`},
+	{"list", http.MethodGet, "/api/v1/products", ""},
+	{"list_limit_1", http.MethodGet, "/api/v1/products?limit=1", ""},
+	{"get", http.MethodGet, "/api/v1/products/1", ""},
+	{"get_not_found", http.MethodGet, "/api/v1/products/999", ""},
+	{"get_invalid_id", http.MethodGet, "/api/v1/products/abc", ""},
+	{"update", http.MethodPut, "/api/v1/products/1", `{"price":34.99,"status":"out_of_stock"}`},
+	{"update_invalid", http.MethodPut, "/api/v1/products/1", `{"status":"unknown"}`},
+	{"update_not_found", http.MethodPut, "/api/v1/products/999", `{"name":"Missing"}`},
+	{"by_category", http.MethodGet, "/api/v1/products/category/1", ""},
+	{"by_category_invalid_id", http.MethodGet, "/api/v1/products/category/abc", ""},
+	{"category_products", http.MethodGet, "/api/v1/categories/1/products", ""},
+	{"delete", http.MethodDelete, "/api/v1/products/2", ""},
+	{"delete_not_found", http.MethodDelete, "/api/v1/products/2", ""},
+	{"list_after_delete", http.MethodGet, "/api/v1/products", ""},
+}
+
+func TestCategoryEndpoints(t *testing.T) {
+	runSteps(t, newTestRouter(t), "categories", categorySteps)
+}
+
+func TestProductEndpoints(t *testing.T) {
+	runSteps(t, newTestRouter(t), "products", productSteps)
+}
+
+// testAuth configures the access tokens of test routers
+var testAuth = config.AuthConfig{
+	SigningKeys:     []string{"test:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="},
+	Issuer:          "ecom-test",
+	AccessTokenTTL:  time.Hour,
+	RefreshTokenTTL: 24 * time.Hour,
+}
+
+func newTokens(t *testing.T) *auth.Tokens {
+	t.Helper()
+	tokens, err := auth.NewTokens(testAuth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens
+}
+
+// catalogStaff is a staff user who may write the catalog and adjust stock
+var catalogStaff = auth.Principal{
+	Kind:        auth.KindStaff,
+	ID:          1,
+	Permissions: []auth.Permission{auth.PermCatalogWrite, auth.PermStockAdjust},
+}
+
+// bearer returns an Authorization header value authenticating p
+func bearer(t *testing.T, p auth.Principal) string {
+	t.Helper()
+	token, _, err := newTokens(t).Issue(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + token
+}
+
+// newTestRouter wires the full application, as cmd/server does, on a fresh
+// migrated schema
+func newTestRouter(t *testing.T) *gin.Engine {
+	return newTestRouterOn(t, pgtest.NewDB(t))
+}
+
+// newTestRouterOn is newTestRouter on db, for tests that also seed it
+func newTestRouterOn(t *testing.T, db *sql.DB) *gin.Engine {
+	tokens := newTokens(t)
+
+	txManager := database.NewTxManager(db, nil)
+	categoryRepo := repositories.NewCategoryRepository(db, 0)
+	productRepo := repositories.NewProductRepository(db, 0)
+	customerRepo := repositories.NewCustomerRepository(db, 0)
+	staffRepo := repositories.NewStaffUserRepository(db, 0)
+	refreshTokenRepo := repositories.NewRefreshTokenRepository(db, 0)
+	roleRepo := repositories.NewRoleRepository(db, 0)
+
+	h := &routes.Handlers{
+		Health:   handlers.NewHealthHandler(db, migrations.NewRunner(db, "")),
+		Category: handlers.NewCategoryHandler(services.NewCategoryService(txManager, categoryRepo, productRepo)),
+		Product:  handlers.NewProductHandler(services.NewProductService(txManager, productRepo, nil)),
+		Auth:     handlers.NewAuthHandler(services.NewAuthService(txManager, customerRepo, staffRepo, refreshTokenRepo, roleRepo, tokens)),
+	}
+
+	router := gin.New()
+	routes.SetupRoutes(router, h, routes.Options{Tokens: tokens})
+	return router
+}
+
+// runSteps sends each request and compares the status and normalised body
+// with testdata/<dir>/<step>.json
+func runSteps(t *testing.T, router http.Handler, dir string, steps []step) {
+	staff := bearer(t, catalogStaff)
+	for _, s := range steps {
+		req := httptest.NewRequest(s.method, s.path, bytes.NewReader([]byte(s.body)))
+		if s.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		// A fixed ID keeps error envelopes stable and checks it is echoed
+		req.Header.Set(requestid.Header, s.name)
+		req.Header.Set("Authorization", staff)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get(requestid.Header); got != s.name {
+			t.Errorf("%s: %s = %q, want %q", s.name, requestid.Header, got, s.name)
+		}
+		golden.Assert(t, path.Join(dir, s.name+".json"), snapshot(t, rec))
+	}
+}
+
+// volatileFields hold wall-clock times and are replaced in snapshots
+var volatileFields = map[string]bool{
+	"timestamp":  true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// snapshot renders a response as indented JSON with volatile fields masked
+func snapshot(t *testing.T, rec *httptest.ResponseRecorder) []byte {
+	t.Helper()
+
+	doc := map[string]interface{}{"status": rec.Code}
+	if rec.Body.Len() > 0 {
+		var body interface{}
+		dec := json.NewDecoder(rec.Body)
+		dec.UseNumber()
+		if err := dec.Decode(&body); err != nil {
+			t.Fatalf("decode response body: %v", err)
+		}
+		doc["body"] = mask(body)
+	}
+
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		t.Fatalf("encode snapshot: %v", err)
+	}
+	return out.Bytes()
+}
+
+func mask(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, ok := value.(string); ok && volatileFields[key] {
+				v[key] = "<time>"
+				continue
+			}
+			v[key] = mask(value)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = mask(v[i])
+		}
+	}
+	return v
+}
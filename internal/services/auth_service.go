@@ -0,0 +1,394 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"ecom/internal/apperrors"
+	"ecom/internal/auth"
+	"ecom/internal/database"
+	"ecom/internal/dto"
+	"ecom/internal/logging"
+	"ecom/internal/models"
+	"ecom/internal/repositories"
+)
+
+// errInvalidCredentials is returned for every failed login, so responses do
+// not reveal which emails have accounts
+var errInvalidCredentials = apperrors.Unauthorized("Invalid email or password")
+
+// errInvalidRefreshToken is returned for unknown, expired, revoked and
+// reused refresh tokens alike
+var errInvalidRefreshToken = apperrors.Unauthorized("The refresh token is invalid or has expired")
+
+// AuthService registers customers and logs customers and staff in and out
+type AuthService struct {
+	tx            database.TxManager
+	customers     repositories.CustomerRepository
+	staff         repositories.StaffUserRepository
+	refreshTokens repositories.RefreshTokenRepository
+	roles         repositories.RoleRepository
+	tokens        *auth.Tokens
+	now           func() time.Time
+}
+
+// NewAuthService creates a new auth service issuing tokens with tokens
+func NewAuthService(tx database.TxManager, customers repositories.CustomerRepository, staff repositories.StaffUserRepository, refreshTokens repositories.RefreshTokenRepository, roles repositories.RoleRepository, tokens *auth.Tokens) *AuthService {
+	return &AuthService{
+		tx:            tx,
+		customers:     customers,
+		staff:         staff,
+		refreshTokens: refreshTokens,
+		roles:         roles,
+		tokens:        tokens,
+		now:           time.Now,
+	}
+}
+
+// Register creates a customer account and logs it in
+func (s *AuthService) Register(ctx context.Context, req *dto.RegisterRequest) (*dto.TokenResponse, error) {
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	customer := &models.Customer{
+		Email:        normalizeEmail(req.Email),
+		FirstName:    req.FirstName,
+		LastName:     req.LastName,
+		IsActive:     true,
+		PasswordHash: hash,
+	}
+	if req.Phone != "" {
+		customer.Phone = &req.Phone
+	}
+
+	var tokens *dto.TokenResponse
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		id, err := s.customers.Create(ctx, customer)
+		if err != nil {
+			return err
+		}
+		tokens, err = s.issue(ctx, auth.Principal{Kind: auth.KindCustomer, ID: id}, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Login logs a customer in with email and password
+func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.TokenResponse, error) {
+	customer, err := s.customers.GetByEmail(ctx, normalizeEmail(req.Email))
+	if err != nil && !apperrors.IsNotFound(err) {
+		return nil, err
+	}
+	if customer == nil || !customer.IsActive || customer.PasswordHash == "" {
+		auth.VerifyNoPassword(req.Password)
+		return nil, errInvalidCredentials
+	}
+
+	principal := auth.Principal{Kind: auth.KindCustomer, ID: customer.ID}
+	if err := s.checkPassword(ctx, principal, customer.PasswordHash, req.Password, s.customers.UpdatePasswordHash); err != nil {
+		return nil, err
+	}
+
+	var tokens *dto.TokenResponse
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.customers.RecordLogin(ctx, customer.ID); err != nil {
+			return err
+		}
+		tokens, err = s.issue(ctx, principal, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// StaffLogin logs a staff user in with email and password
+func (s *AuthService) StaffLogin(ctx context.Context, req *dto.LoginRequest) (*dto.TokenResponse, error) {
+	user, err := s.staff.GetByEmail(ctx, normalizeEmail(req.Email))
+	if err != nil && !apperrors.IsNotFound(err) {
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		auth.VerifyNoPassword(req.Password)
+		return nil, errInvalidCredentials
+	}
+
+	principal := auth.Principal{Kind: auth.KindStaff, ID: user.ID}
+	if err := s.checkPassword(ctx, principal, user.PasswordHash, req.Password, s.staff.UpdatePasswordHash); err != nil {
+		return nil, err
+	}
+
+	var tokens *dto.TokenResponse
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.staff.RecordLogin(ctx, user.ID); err != nil {
+			return err
+		}
+		tokens, err = s.issue(ctx, principal, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair in the same
+// family. Each refresh token is accepted once: presenting one that was
+// already exchanged means it was copied, so the whole family is revoked
+// and whoever holds the latest token has to log in again. Two concurrent
+// refreshes with one token count as reuse too.
+func (s *AuthService) Refresh(ctx context.Context, req *dto.RefreshTokenRequest) (*dto.TokenResponse, error) {
+	var (
+		tokens *dto.TokenResponse
+		reused *models.RefreshToken
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		stored, err := s.refreshTokens.GetByHashForUpdate(ctx, auth.HashRefreshToken(req.RefreshToken))
+		if apperrors.IsNotFound(err) {
+			return errInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+
+		if stored.RevokedAt != nil {
+			return errInvalidRefreshToken
+		}
+		if stored.RotatedAt != nil {
+			// Committed before the error is returned, below
+			reused = stored
+			return s.refreshTokens.RevokeFamily(ctx, stored.FamilyID)
+		}
+		if !s.now().Before(stored.ExpiresAt) {
+			return errInvalidRefreshToken
+		}
+
+		principal, err := s.activePrincipal(ctx, stored)
+		if err != nil {
+			return err
+		}
+		if err := s.refreshTokens.MarkRotated(ctx, stored.ID); err != nil {
+			return err
+		}
+		tokens, err = s.issue(ctx, principal, stored.FamilyID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reused != nil {
+		logging.FromContext(ctx).Warn("refresh token reused; revoked its family",
+			slog.String("family_id", reused.FamilyID),
+			slog.Int64("token_id", reused.ID),
+		)
+		return nil, errInvalidRefreshToken
+	}
+	return tokens, nil
+}
+
+// Logout revokes the family of a refresh token, ending the login it came
+// from. Access tokens already issued stay valid until they expire. Unknown
+// and already revoked tokens are ignored, so logging out twice succeeds.
+func (s *AuthService) Logout(ctx context.Context, req *dto.RefreshTokenRequest) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		stored, err := s.refreshTokens.GetByHashForUpdate(ctx, auth.HashRefreshToken(req.RefreshToken))
+		if apperrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return s.refreshTokens.RevokeFamily(ctx, stored.FamilyID)
+	})
+}
+
+// Me describes the authenticated caller
+func (s *AuthService) Me(ctx context.Context) (*dto.PrincipalResponse, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, apperrors.Unauthorized("Authentication is required")
+	}
+
+	if principal.IsStaff() {
+		user, err := s.staff.GetByID(ctx, principal.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.PrincipalResponse{
+			Kind:        string(principal.Kind),
+			ID:          user.ID,
+			Email:       user.Email,
+			Name:        user.Name,
+			Permissions: permissionNames(principal.Permissions),
+		}, nil
+	}
+
+	customer, err := s.customers.GetByID(ctx, principal.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PrincipalResponse{
+		Kind:        string(principal.Kind),
+		ID:          customer.ID,
+		Email:       customer.Email,
+		Name:        strings.TrimSpace(customer.FirstName + " " + customer.LastName),
+		Permissions: permissionNames(principal.Permissions),
+	}, nil
+}
+
+// CreateStaffUser creates a staff account holding roles; there is no API
+// for it
+func (s *AuthService) CreateStaffUser(ctx context.Context, email, name, password string, roles []string) (int64, error) {
+	if len(password) < auth.MinPasswordLength {
+		return 0, apperrors.Validation("password must be at least 8 characters", "password")
+	}
+	for i, role := range roles {
+		roles[i] = strings.TrimSpace(role)
+		if roles[i] == auth.RoleCustomer {
+			return 0, apperrors.Validation("the customer role is not for staff users", "role")
+		}
+	}
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		id, err = s.staff.Create(ctx, &models.StaffUser{
+			Email:        normalizeEmail(email),
+			Name:         name,
+			PasswordHash: hash,
+			IsActive:     true,
+		})
+		if err != nil {
+			return err
+		}
+		return s.roles.AssignToStaffUser(ctx, id, roles)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// checkPassword verifies password against hash and, on a match, replaces a
+// hash that is bcrypt or weaker than current parameters. A failed rehash
+// is logged: the login itself succeeded.
+func (s *AuthService) checkPassword(ctx context.Context, principal auth.Principal, hash, password string, rehash func(ctx context.Context, id int64, hash string) error) error {
+	ok, needsRehash, err := auth.VerifyPassword(hash, password)
+	if err != nil {
+		logging.FromContext(ctx).Error("unreadable password hash",
+			slog.String("principal", principal.String()), slog.Any("error", err))
+		return errInvalidCredentials
+	}
+	if !ok {
+		return errInvalidCredentials
+	}
+	if needsRehash {
+		newHash, err := auth.HashPassword(password)
+		if err == nil {
+			err = rehash(ctx, principal.ID, newHash)
+		}
+		if err != nil {
+			logging.FromContext(ctx).Warn("failed to upgrade password hash",
+				slog.String("principal", principal.String()), slog.Any("error", err))
+		}
+	}
+	return nil
+}
+
+// activePrincipal returns the owner of a refresh token, which must still be
+// able to log in
+func (s *AuthService) activePrincipal(ctx context.Context, token *models.RefreshToken) (auth.Principal, error) {
+	if token.StaffUserID != nil {
+		user, err := s.staff.GetByID(ctx, *token.StaffUserID)
+		if apperrors.IsNotFound(err) || (err == nil && !user.IsActive) {
+			return auth.Principal{}, errInvalidRefreshToken
+		}
+		return auth.Principal{Kind: auth.KindStaff, ID: *token.StaffUserID}, err
+	}
+
+	customer, err := s.customers.GetByID(ctx, *token.CustomerID)
+	if apperrors.IsNotFound(err) || (err == nil && !customer.IsActive) {
+		return auth.Principal{}, errInvalidRefreshToken
+	}
+	return auth.Principal{Kind: auth.KindCustomer, ID: *token.CustomerID}, err
+}
+
+// issue returns a new token pair for principal, storing the refresh token
+// in familyID or, when it is empty, a new family. The access token carries
+// the permissions principal's roles grant now, so role changes apply at the
+// next login or refresh.
+func (s *AuthService) issue(ctx context.Context, principal auth.Principal, familyID string) (*dto.TokenResponse, error) {
+	var (
+		permissions []string
+		err         error
+	)
+	if principal.IsStaff() {
+		permissions, err = s.roles.PermissionsOfStaffUser(ctx, principal.ID)
+	} else {
+		permissions, err = s.roles.PermissionsOfRole(ctx, auth.RoleCustomer)
+	}
+	if err != nil {
+		return nil, err
+	}
+	principal.Permissions = make([]auth.Permission, len(permissions))
+	for i, permission := range permissions {
+		principal.Permissions[i] = auth.Permission(permission)
+	}
+
+	accessToken, _, err := s.tokens.Issue(principal)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, hash, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &models.RefreshToken{
+		TokenHash: hash,
+		FamilyID:  familyID,
+		ExpiresAt: s.now().Add(s.tokens.RefreshTTL()),
+	}
+	if principal.IsStaff() {
+		stored.StaffUserID = &principal.ID
+	} else {
+		stored.CustomerID = &principal.ID
+	}
+	if _, err := s.refreshTokens.Create(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	return &dto.TokenResponse{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(s.tokens.AccessTTL().Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: stored.ExpiresAt.UTC(),
+	}, nil
+}
+
+// permissionNames returns permissions as strings, never nil
+func permissionNames(permissions []auth.Permission) []string {
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = string(permission)
+	}
+	return names
+}
+
+// normalizeEmail lower-cases an email, the form accounts are stored and
+// looked up in
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -1,325 +1,201 @@
 package services
 
 import (
-	"database/sql"
-	"fmt"
-	"log"
+	"context"
 
+	"ecom/internal/auth"
+	"ecom/internal/database"
 	"ecom/internal/dto"
+	"ecom/internal/metrics"
+	"ecom/internal/models"
+	"ecom/internal/repositories"
 )
 
 // ProductService handles product business logic
 type ProductService struct {
-	db *sql.DB
+	tx       database.TxManager
+	products repositories.ProductRepository
+	business *metrics.Business
 }
 
-// NewProductService creates a new product service
-func NewProductService(db *sql.DB) *ProductService {
-	return &ProductService{db: db}
+// NewProductService creates a new product service. business may be nil to
+// skip the stock movement counters.
+func NewProductService(tx database.TxManager, products repositories.ProductRepository, business *metrics.Business) *ProductService {
+	return &ProductService{tx: tx, products: products, business: business}
 }
 
-// CreateProduct creates a new product
-func (s *ProductService) CreateProduct(req *dto.CreateProductRequest) (*dto.ProductResponse, error) {
-	var id int64
-
-	query := `
-		INSERT INTO products (sku, name, slug, description, short_description, category_id, status, price, compare_at_price, stock_quantity, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		RETURNING id
-	`
-	err := s.db.QueryRow(
-		query,
-		req.SKU,
-		req.Name,
-		req.Slug,
-		req.Description,
-		req.ShortDescription,
-		req.CategoryID,
-		req.Status,
-		req.Price,
-		req.CompareAtPrice,
-		req.StockQuantity,
-	).Scan(&id)
+// CreateProduct creates a new product. Its opening stock is recorded as an
+// adjustment inventory movement.
+func (s *ProductService) CreateProduct(ctx context.Context, req *dto.CreateProductRequest) (*dto.ProductResponse, error) {
+	var (
+		product    *dto.ProductResponse
+		stockDelta int
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		id, err := s.products.Create(ctx, req)
+		if err != nil {
+			return err
+		}
+		stockDelta, err = s.products.SetStock(ctx, id, req.StockQuantity, "Opening stock", actor(ctx))
+		if err != nil {
+			return err
+		}
 
+		// Read the created product back in the same transaction
+		product, err = s.GetProductByID(ctx, id)
+		return err
+	})
 	if err != nil {
-		log.Printf("Error creating product: %v", err)
-		return nil, fmt.Errorf("failed to create product: %w", err)
-	}	
+		return nil, err
+	}
 
-	// Fetch and return the created product
-	return s.GetProductByID(id)
+	s.business.StockMoved(metrics.StockAdjustment, stockDelta)
+	return product, nil
 }
 
-func(s *ProductService) GetProductByID(id int64) (*dto.ProductResponse, error) {
-	var product dto.ProductResponse
-
-	query := `SELECT id, sku, name, slug, description, short_description, category_id, status, price, compare_at_price, cost_price, stock_quantity, low_stock_threshold, weight_kg, dimensions_cm, barcode, manufacturer, brand, rating_average, rating_count, view_count, is_featured, meta_title, meta_description, created_at, updated_at 
-	 FROM products 
-	 WHERE id = $1 AND deleted_at IS NULL`
-
-	 err := s.db.QueryRow(query, id).Scan(
-		&product.ID,
-		&product.SKU,
-		&product.Name,
-		&product.Slug,
-		&product.Description,
-		&product.ShortDescription,
-		&product.CategoryID,
-		&product.Status,
-		&product.Price,
-		&product.CompareAtPrice,
-		&product.CostPrice,
-		&product.StockQuantity,
-		&product.LowStockThreshold,
-		&product.WeightKg,
-		&product.DimensionsCm,
-		&product.Barcode,
-		&product.Manufacturer,
-		&product.Brand,
-		&product.RatingAverage,
-		&product.RatingCount,
-		&product.ViewCount,
-		&product.IsFeautred,
-		&product.MetaTitle,
-		&product.MetaDescription,
-		&product.CreatedAt,
-		&product.UpdatedAt,	
-	 )
-
-	 if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("product not found")
-	 }
-
-	 if err != nil {
-		log.Printf("Error fetching product by ID: %v", err)
-		return nil, fmt.Errorf("failed to fetch product: %w", err)
-	 }
+// GetProductByID retrieves a product by ID
+func (s *ProductService) GetProductByID(ctx context.Context, id int64) (*dto.ProductResponse, error) {
+	product, err := s.products.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
 
-	 return &product, nil
+	response := toProductResponse(product)
+	return &response, nil
 }
 
 // GetAllProductsByCategory retrieves products by category with pagination
-func (s *ProductService) GetAllProductsByCategory(categoryID int64, page, limit int) ([]dto.ProductResponse, int, error) {
-	offset := (page - 1) * limit
-	
-	// Get total count
-	var total int
-	countQuery := `SELECT COUNT(*) FROM products WHERE category_id = $1 AND deleted_at IS NULL`
-	err := s.db.QueryRow(countQuery, categoryID).Scan(&total)
+func (s *ProductService) GetAllProductsByCategory(ctx context.Context, categoryID int64, page, limit int) ([]dto.ProductResponse, int, error) {
+	total, err := s.products.CountByCategory(ctx, categoryID)
 	if err != nil {
-		log.Printf("Error counting products: %v", err)
-		return nil, 0, fmt.Errorf("failed to count products: %w", err)
+		return nil, 0, err
 	}
 
-	// Get paginated results
-	query := `
-		SELECT id, sku, name, slug, description, short_description, category_id, status, price, 
-		       compare_at_price, cost_price, stock_quantity, low_stock_threshold, weight_kg, 
-		       dimensions_cm, barcode, manufacturer, brand, rating_average, rating_count, 
-		       view_count, is_featured, meta_title, meta_description, created_at, updated_at
-		FROM products
-		WHERE category_id = $1 AND deleted_at IS NULL
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
-	`
-	
-	rows, err := s.db.Query(query, categoryID, limit, offset)
+	products, err := s.products.ListByCategory(ctx, categoryID, limit, (page-1)*limit)
 	if err != nil {
-		log.Printf("Error fetching products: %v", err)
-		return nil, 0, fmt.Errorf("failed to fetch products: %w", err)
-	}
-	defer rows.Close()
-	
-	var products []dto.ProductResponse
-	for rows.Next() {
-		var product dto.ProductResponse
-		err := rows.Scan(
-			&product.ID,
-			&product.SKU,
-			&product.Name,
-			&product.Slug,
-			&product.Description,
-			&product.ShortDescription,
-			&product.CategoryID,
-			&product.Status,
-			&product.Price,
-			&product.CompareAtPrice,
-			&product.CostPrice,
-			&product.StockQuantity,
-			&product.LowStockThreshold,
-			&product.WeightKg,
-			&product.DimensionsCm,
-			&product.Barcode,
-			&product.Manufacturer,
-			&product.Brand,
-			&product.RatingAverage,
-			&product.RatingCount,
-			&product.ViewCount,
-			&product.IsFeautred,
-			&product.MetaTitle,
-			&product.MetaDescription,
-			&product.CreatedAt,
-			&product.UpdatedAt,	
-		)
-		if err != nil {
-			log.Printf("Error scanning product row: %v", err)
-			return nil, 0, fmt.Errorf("failed to scan product: %w", err)
-		}
-		products = append(products, product)
+		return nil, 0, err
 	}
 
-	if err = rows.Err(); err != nil {
-		log.Printf("Row iteration error: %v", err)
-		return nil, 0, fmt.Errorf("row iteration error: %w", err)
+	return toProductResponses(products), total, nil
+}
+
+// UpdateProduct updates an existing product. Changing the stock quantity
+// needs auth.PermStockAdjust and changing anything else auth.PermCatalogWrite.
+func (s *ProductService) UpdateProduct(ctx context.Context, productID int64, req *dto.UpdateProductRequest) (*dto.ProductResponse, error) {
+	if err := authorizeProductUpdate(ctx, req); err != nil {
+		return nil, err
 	}
 
-	return products, total, nil
-}	
+	var (
+		product    *dto.ProductResponse
+		stockDelta int
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.products.Update(ctx, productID, req); err != nil {
+			return err
+		}
 
-// UpdateProduct updates an existing product
-func (s *ProductService) UpdateProduct(productID int64, req *dto.UpdateProductRequest) (*dto.ProductResponse, error) {
-	query := `
-		UPDATE products SET
-			name = COALESCE(NULLIF($1, ''), name),
-			slug = COALESCE(NULLIF($2, ''), slug),
-			description = COALESCE(NULLIF($3, ''), description),
-			short_description = COALESCE(NULLIF($4, ''), short_description),
-			category_id = COALESCE($5, category_id),
-			status = COALESCE(NULLIF($6, ''), status),
-			price = COALESCE($7, price),
-			compare_at_price = COALESCE($8, compare_at_price),
-			updated_at = CURRENT_TIMESTAMP
-		WHERE id = $9 AND deleted_at IS NULL
-		RETURNING id
-	`
-	var id int64
-	err := s.db.QueryRow(
-		query,
-		req.Name,
-		req.Slug,
-		req.Description,
-		req.ShortDescription,
-		req.CategoryID,		
-		req.Status,	
-		req.Price,
-		req.CompareAtPrice,
-		productID,
-	).Scan(&id)
-	
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("product not found")
-	}
+		// Setting the quantity is an adjustment movement by the difference
+		var err error
+		stockDelta = 0
+		if req.StockQuantity != nil {
+			stockDelta, err = s.products.SetStock(ctx, productID, *req.StockQuantity, "", actor(ctx))
+			if err != nil {
+				return err
+			}
+		}
 
+		// Read the updated product back in the same transaction
+		product, err = s.GetProductByID(ctx, productID)
+		return err
+	})
 	if err != nil {
-		log.Printf("Error updating product: %v", err)
-		return nil, fmt.Errorf("failed to update product: %w", err)
+		return nil, err
 	}
 
-	// Fetch and return the updated product
-	return s.GetProductByID(id)
+	s.business.StockMoved(metrics.StockAdjustment, stockDelta)
+	return product, nil
 }
 
-// GetAllProducts retrieves all products with pagination
-func (s *ProductService) GetAllProducts(page, limit int) ([]dto.ProductResponse, int, error) {
-	offset := (page - 1) * limit
-	
-	// Get total count
-	var total int
-	countQuery := `SELECT COUNT(*) FROM products WHERE deleted_at IS NULL`
-	err := s.db.QueryRow(countQuery).Scan(&total)
-	if err != nil {
-		log.Printf("Error counting products: %v", err)
-		return nil, 0, fmt.Errorf("failed to count products: %w", err)
-	}
-
-	// Get paginated results
-	query := `
-		SELECT id, sku, name, slug, description, short_description, category_id, status, price, 
-		       compare_at_price, cost_price, stock_quantity, low_stock_threshold, weight_kg, 
-		       dimensions_cm, barcode, manufacturer, brand, rating_average, rating_count, 
-		       view_count, is_featured, meta_title, meta_description, created_at, updated_at
-		FROM products
-		WHERE deleted_at IS NULL
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
-	
-	rows, err := s.db.Query(query, limit, offset)
-	if err != nil {
-		log.Printf("Error fetching products: %v", err)
-		return nil, 0, fmt.Errorf("failed to fetch products: %w", err)
-	}
-	defer rows.Close()
-	
-	var products []dto.ProductResponse
-	for rows.Next() {
-		var product dto.ProductResponse
-		err := rows.Scan(
-			&product.ID,
-			&product.SKU,
-			&product.Name,
-			&product.Slug,
-			&product.Description,
-			&product.ShortDescription,
-			&product.CategoryID,
-			&product.Status,
-			&product.Price,
-			&product.CompareAtPrice,
-			&product.CostPrice,
-			&product.StockQuantity,
-			&product.LowStockThreshold,
-			&product.WeightKg,
-			&product.DimensionsCm,
-			&product.Barcode,
-			&product.Manufacturer,
-			&product.Brand,
-			&product.RatingAverage,
-			&product.RatingCount,
-			&product.ViewCount,
-			&product.IsFeautred,
-			&product.MetaTitle,
-			&product.MetaDescription,
-			&product.CreatedAt,
-			&product.UpdatedAt,	
-		)
-		if err != nil {
-			log.Printf("Error scanning product row: %v", err)
-			return nil, 0, fmt.Errorf("failed to scan product: %w", err)
+// authorizeProductUpdate checks the permissions the fields set in req need
+func authorizeProductUpdate(ctx context.Context, req *dto.UpdateProductRequest) error {
+	if req.StockQuantity != nil {
+		if err := auth.Authorize(ctx, auth.PermStockAdjust); err != nil {
+			return err
 		}
-		products = append(products, product)
 	}
-
-	if err = rows.Err(); err != nil {
-		log.Printf("Row iteration error: %v", err)
-		return nil, 0, fmt.Errorf("row iteration error: %w", err)
+	catalog := *req
+	catalog.StockQuantity = nil
+	if catalog != (dto.UpdateProductRequest{}) {
+		return auth.Authorize(ctx, auth.PermCatalogWrite)
 	}
-
-	return products, total, nil
+	return nil
 }
 
-// DeleteProduct soft deletes a product
-func (s *ProductService) DeleteProduct(id int64) error {
-	query := `
-		UPDATE products
-		SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $1 AND deleted_at IS NULL
-	`
+// actor names the caller in ctx for inventory_movements.created_by, or is
+// empty when there is none
+func actor(ctx context.Context) string {
+	if principal, ok := auth.FromContext(ctx); ok {
+		return principal.String()
+	}
+	return ""
+}
 
-	result, err := s.db.Exec(query, id)
+// GetAllProducts retrieves all products with pagination
+func (s *ProductService) GetAllProducts(ctx context.Context, page, limit int) ([]dto.ProductResponse, int, error) {
+	total, err := s.products.Count(ctx)
 	if err != nil {
-		log.Printf("Error deleting product: %v", err)
-		return fmt.Errorf("failed to delete product: %w", err)
+		return nil, 0, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	products, err := s.products.List(ctx, limit, (page-1)*limit)
 	if err != nil {
-		log.Printf("Error getting rows affected: %v", err)
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return nil, 0, err
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("product not found")
+	return toProductResponses(products), total, nil
+}
+
+// DeleteProduct soft deletes a product
+func (s *ProductService) DeleteProduct(ctx context.Context, id int64) error {
+	return s.products.Delete(ctx, id)
+}
+
+func toProductResponse(p *models.Product) dto.ProductResponse {
+	return dto.ProductResponse{
+		ID:                p.ID,
+		SKU:               p.SKU,
+		Name:              p.Name,
+		Slug:              p.Slug,
+		Description:       p.Description,
+		ShortDescription:  p.ShortDescription,
+		CategoryID:        p.CategoryID,
+		Status:            p.Status,
+		Price:             p.Price,
+		CompareAtPrice:    p.CompareAtPrice,
+		CostPrice:         p.CostPrice,
+		StockQuantity:     p.StockQuantity,
+		LowStockThreshold: p.LowStockThreshold,
+		WeightKg:          p.WeightKg,
+		DimensionsCm:      p.DimensionsCm,
+		Barcode:           p.Barcode,
+		Manufacturer:      p.Manufacturer,
+		Brand:             p.Brand,
+		RatingAverage:     p.RatingAverage,
+		RatingCount:       p.RatingCount,
+		ViewCount:         p.ViewCount,
+		IsFeautred:        p.IsFeatured,
+		MetaTitle:         p.MetaTitle,
+		MetaDescription:   p.MetaDescription,
+		CreatedAt:         p.CreatedAt,
+		UpdatedAt:         p.UpdatedAt,
 	}
+}
 
-	return nil
-}
\ No newline at end of file
+func toProductResponses(products []models.Product) []dto.ProductResponse {
+	var responses []dto.ProductResponse
+	for i := range products {
+		responses = append(responses, toProductResponse(&products[i]))
+	}
+	return responses
+}
@@ -1,23 +1,25 @@
 package services
 
 import (
+	"context"
+
 	"ecom/internal/models"
 	"ecom/internal/repositories"
 )
 
 // UserService handles user-related business logic
 type UserService struct {
-	userRepo *repositories.UserRepository
+	users repositories.UserRepository
 }
 
 // NewUserService creates a new user service
-func NewUserService() *UserService {
+func NewUserService(users repositories.UserRepository) *UserService {
 	return &UserService{
-		userRepo: repositories.NewUserRepository(),
+		users: users,
 	}
 }
 
 // GetUser retrieves a user by ID
-func (s *UserService) GetUser(id int) (*models.User, error) {
-	return s.userRepo.GetUserByID(id)
+func (s *UserService) GetUser(ctx context.Context, id int) (*models.User, error) {
+	return s.users.GetByID(ctx, id)
 }
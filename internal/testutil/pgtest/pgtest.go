@@ -0,0 +1,230 @@
+// Package pgtest runs integration tests against a throwaway PostgreSQL
+// server. RunMain starts one server per test binary from the local
+// PostgreSQL binaries (initdb and pg_ctl); no containers are involved. Each
+// test then gets its own schema with all migrations applied, so tests are
+// isolated from each other and sequences start at 1.
+//
+// Set TEST_DATABASE_URL to use an existing server instead, and PG_BIN to the
+// directory holding initdb and pg_ctl when they are not on PATH. Tests are
+// skipped when neither is available.
+package pgtest
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ecom/migrations"
+
+	_ "github.com/lib/pq"
+)
+
+// extensions are created once in the public schema; the migrations'
+// CREATE EXTENSION IF NOT EXISTS statements are then no-ops in test schemas
+var extensions = []string{"uuid-ossp", "pg_trgm", "btree_gin"}
+
+// server is the PostgreSQL server shared by the tests of this binary
+var server struct {
+	dsn string
+	// unavailable explains why tests are skipped when there is no server
+	unavailable string
+}
+
+// RunMain starts the server, runs the tests and stops the server again. Call
+// it from TestMain:
+//
+//	func TestMain(m *testing.M) { os.Exit(pgtest.RunMain(m)) }
+func RunMain(m *testing.M) int {
+	stop, err := start()
+	if err != nil {
+		server.unavailable = err.Error()
+		log.Printf("pgtest: %v; database tests will be skipped", err)
+		return m.Run()
+	}
+	defer stop()
+
+	return m.Run()
+}
+
+// NewDB returns a connection pool whose search_path is a new schema with all
+// migrations applied. The schema is dropped when the test finishes.
+func NewDB(t testing.TB) *sql.DB {
+	t.Helper()
+
+	if server.dsn == "" {
+		t.Skipf("no PostgreSQL server: %s", server.unavailable)
+	}
+
+	admin, err := sql.Open("postgres", server.dsn)
+	if err != nil {
+		t.Fatalf("pgtest: open admin connection: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("test_%x", rand.Uint64())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatalf("pgtest: create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Errorf("pgtest: drop schema %s: %v", schema, err)
+		}
+	})
+
+	dsn, err := withSearchPath(server.dsn, schema+",public")
+	if err != nil {
+		t.Fatalf("pgtest: %v", err)
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("pgtest: open test connection: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := migrations.NewRunner(db, "").Up(); err != nil {
+		t.Fatalf("pgtest: apply migrations: %v", err)
+	}
+	return db
+}
+
+// start connects to TEST_DATABASE_URL, or initialises and starts a server in
+// a temporary directory. The returned func stops it.
+func start() (func(), error) {
+	if dsn := os.Getenv("TEST_DATABASE_URL"); dsn != "" {
+		if err := prepare(dsn); err != nil {
+			return nil, err
+		}
+		server.dsn = dsn
+		return func() {}, nil
+	}
+
+	bin, err := findBinaries()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "pgtest-")
+	if err != nil {
+		return nil, fmt.Errorf("create data directory: %w", err)
+	}
+	data := filepath.Join(dir, "data")
+
+	initdb := exec.Command(filepath.Join(bin, "initdb"), "-D", data, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-sync")
+	if out, err := initdb.CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("initdb: %v\n%s", err, out)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	// Listen on a Unix socket in the temporary directory only, and trade
+	// durability for speed
+	options := fmt.Sprintf("-p %d -k %s -c listen_addresses='' -c fsync=off -c synchronous_commit=off -c full_page_writes=off", port, dir)
+	pgCtl := filepath.Join(bin, "pg_ctl")
+	startCmd := exec.Command(pgCtl, "start", "-D", data, "-w", "-l", filepath.Join(dir, "postgres.log"), "-o", options)
+	if out, err := startCmd.CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("pg_ctl start: %v\n%s", err, out)
+	}
+
+	stop := func() {
+		stopCmd := exec.Command(pgCtl, "stop", "-D", data, "-m", "immediate")
+		if out, err := stopCmd.CombinedOutput(); err != nil {
+			log.Printf("pgtest: pg_ctl stop: %v\n%s", err, out)
+		}
+		os.RemoveAll(dir)
+	}
+
+	dsn := fmt.Sprintf("host=%s port=%d user=postgres dbname=postgres sslmode=disable", dir, port)
+	if err := prepare(dsn); err != nil {
+		stop()
+		return nil, err
+	}
+	server.dsn = dsn
+	return stop, nil
+}
+
+// prepare checks the server is reachable and creates the extensions the
+// migrations depend on
+func prepare(dsn string) error {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", dsn, err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	for _, ext := range extensions {
+		if _, err := db.Exec(fmt.Sprintf(`CREATE EXTENSION IF NOT EXISTS "%s" SCHEMA public`, ext)); err != nil {
+			return fmt.Errorf("create extension %s: %w", ext, err)
+		}
+	}
+	return nil
+}
+
+// findBinaries returns the directory holding initdb and pg_ctl
+func findBinaries() (string, error) {
+	if dir := os.Getenv("PG_BIN"); dir != "" {
+		return dir, nil
+	}
+	if path, err := exec.LookPath("pg_ctl"); err == nil {
+		return filepath.Dir(path), nil
+	}
+
+	// Debian/Ubuntu and Homebrew keep the server binaries off PATH
+	for _, pattern := range []string{
+		"/usr/lib/postgresql/*/bin",
+		"/usr/local/opt/postgresql*/bin",
+		"/opt/homebrew/opt/postgresql*/bin",
+	} {
+		matches, _ := filepath.Glob(pattern)
+		for i := len(matches) - 1; i >= 0; i-- {
+			if _, err := os.Stat(filepath.Join(matches[i], "pg_ctl")); err == nil {
+				return matches[i], nil
+			}
+		}
+	}
+
+	return "", errors.New("initdb and pg_ctl not found (set PG_BIN or TEST_DATABASE_URL)")
+}
+
+// freePort asks the kernel for a port that is currently unused
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("find a free port: %w", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// withSearchPath sets the search_path run-time parameter on a key=value or
+// URL connection string
+func withSearchPath(dsn, searchPath string) (string, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", fmt.Errorf("parse TEST_DATABASE_URL: %w", err)
+		}
+		q := u.Query()
+		q.Set("search_path", searchPath)
+		u.RawQuery = q.Encode()
+		return u.String(), nil
+	}
+	return dsn + " search_path=" + searchPath, nil
+}
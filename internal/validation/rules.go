@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	// slugPattern: lowercase words separated by single hyphens, e.g. "gaming-laptops"
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	// skuPattern: uppercase alphanumeric segments separated by - or _, e.g. "LAP-001"
+	skuPattern = regexp.MustCompile(`^[A-Z0-9]+(?:[-_][A-Z0-9]+)*$`)
+	// barcodePattern is the shape ValidBarcode accepts; it cannot express the check digit
+	barcodePattern = regexp.MustCompile(`^(?:[0-9]{8}|[0-9]{12,14})$`)
+)
+
+// currencyCodes are the ISO 4217 codes accepted for prices, orders and payments
+var currencyCodes = map[string]bool{
+	"AED": true, "ARS": true, "AUD": true, "BDT": true, "BGN": true, "BRL": true, "CAD": true, "CHF": true,
+	"CLP": true, "CNY": true, "COP": true, "CZK": true, "DKK": true, "EGP": true, "EUR": true, "GBP": true,
+	"HKD": true, "HUF": true, "IDR": true, "ILS": true, "INR": true, "JPY": true, "KES": true, "KRW": true,
+	"KWD": true, "MXN": true, "MYR": true, "NGN": true, "NOK": true, "NZD": true, "PEN": true, "PHP": true,
+	"PKR": true, "PLN": true, "QAR": true, "RON": true, "SAR": true, "SEK": true, "SGD": true, "THB": true,
+	"TRY": true, "TWD": true, "UAH": true, "USD": true, "VND": true, "ZAR": true,
+}
+
+type customRule struct {
+	tag     string
+	fn      validator.Func
+	message string
+}
+
+var customRules = []customRule{
+	{
+		tag:     "slug",
+		fn:      func(fl validator.FieldLevel) bool { return slugPattern.MatchString(fl.Field().String()) },
+		message: "{0} must contain only lowercase letters, digits and single hyphens",
+	},
+	{
+		tag:     "sku",
+		fn:      func(fl validator.FieldLevel) bool { return skuPattern.MatchString(fl.Field().String()) },
+		message: "{0} must contain only uppercase letters and digits, separated by - or _",
+	},
+	{
+		tag:     "currency",
+		fn:      func(fl validator.FieldLevel) bool { return currencyCodes[fl.Field().String()] },
+		message: "{0} must be a supported ISO 4217 currency code",
+	},
+	{
+		tag:     "barcode",
+		fn:      func(fl validator.FieldLevel) bool { return ValidBarcode(fl.Field().String()) },
+		message: "{0} must be a valid EAN-8, UPC-A, EAN-13 or GTIN-14 barcode",
+	},
+}
+
+// Pattern returns the regular expression behind a custom string rule, so the
+// API documentation can state it. ok is false for rules without one.
+func Pattern(tag string) (pattern string, ok bool) {
+	switch tag {
+	case "slug":
+		return slugPattern.String(), true
+	case "sku":
+		return skuPattern.String(), true
+	case "barcode":
+		return barcodePattern.String(), true
+	}
+	return "", false
+}
+
+// CurrencyCodes returns the codes accepted by the currency rule, sorted
+func CurrencyCodes() []string {
+	codes := make([]string, 0, len(currencyCodes))
+	for code := range currencyCodes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// ValidBarcode reports whether code is an EAN-8, UPC-A (12), EAN-13 or
+// GTIN-14 number with a correct GS1 check digit
+func ValidBarcode(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < len(code)-1; i++ {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		// Weights alternate 3,1,3,... counting from the digit next to the check digit
+		weight := 1
+		if (len(code)-1-i)%2 == 1 {
+			weight = 3
+		}
+		sum += int(c-'0') * weight
+	}
+
+	check := code[len(code)-1]
+	if check < '0' || check > '9' {
+		return false
+	}
+	return int(check-'0') == (10-sum%10)%10
+}
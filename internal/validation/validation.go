@@ -0,0 +1,169 @@
+// Package validation configures gin's request validator: JSON field names in
+// errors, English translations of every rule and the custom rules used by the
+// request DTOs (slug, sku, currency, barcode).
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"ecom/internal/dto"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+)
+
+var (
+	setupOnce  sync.Once
+	setupErr   error
+	translator ut.Translator
+)
+
+// Setup registers the custom rules and translations on gin's validator. It is
+// safe to call more than once.
+func Setup() error {
+	setupOnce.Do(func() {
+		setupErr = setup()
+	})
+	return setupErr
+}
+
+func setup() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("unexpected validator engine %T", binding.Validator.Engine())
+	}
+
+	// Report fields by their JSON name instead of the Go struct field
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	english := en.New()
+	translator, _ = ut.New(english, english).GetTranslator("en")
+	if err := en_translations.RegisterDefaultTranslations(v, translator); err != nil {
+		return fmt.Errorf("failed to register translations: %w", err)
+	}
+
+	for _, rule := range customRules {
+		if err := v.RegisterValidation(rule.tag, rule.fn); err != nil {
+			return fmt.Errorf("failed to register %s validator: %w", rule.tag, err)
+		}
+		if err := registerMessage(v, rule.tag, rule.message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// registerMessage adds an English message for a custom rule; {0} is the field
+func registerMessage(v *validator.Validate, tag, message string) error {
+	err := v.RegisterTranslation(tag, translator,
+		func(t ut.Translator) error {
+			return t.Add(tag, message, true)
+		},
+		func(t ut.Translator, fe validator.FieldError) string {
+			msg, err := t.T(tag, fe.Field())
+			if err != nil {
+				return fe.Error()
+			}
+			return msg
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register %s translation: %w", tag, err)
+	}
+	return nil
+}
+
+// Details converts a binding error into per-field details. Errors that are
+// not about a specific field (such as malformed JSON) yield a single entry
+// without a field.
+func Details(err error) []dto.FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		details := make([]dto.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			details = append(details, dto.FieldError{
+				Field:   fieldPath(fe),
+				Rule:    fe.Tag(),
+				Param:   fe.Param(),
+				Message: translate(fe),
+			})
+		}
+		return details
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []dto.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Param:   typeErr.Type.String(),
+			Message: fmt.Sprintf("%s must be of type %s", typeErr.Field, jsonType(typeErr.Type)),
+		}}
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return []dto.FieldError{{Rule: "json", Message: fmt.Sprintf("Request body is not valid JSON (offset %d)", syntaxErr.Offset)}}
+	}
+	if errors.Is(err, io.EOF) {
+		return []dto.FieldError{{Rule: "json", Message: "Request body is empty"}}
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return []dto.FieldError{{Rule: "json", Message: "Request body is not valid JSON (unexpected end of input)"}}
+	}
+
+	return []dto.FieldError{{Rule: "body", Message: err.Error()}}
+}
+
+// fieldPath returns the JSON path of the field without the request struct
+// name, e.g. "items[0].quantity" rather than "CreateOrderRequest.items[0].quantity"
+func fieldPath(fe validator.FieldError) string {
+	if _, path, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return path
+	}
+	return fe.Field()
+}
+
+func translate(fe validator.FieldError) string {
+	if translator == nil {
+		return fe.Error()
+	}
+	return fe.Translate(translator)
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return "string"
+	}
+}
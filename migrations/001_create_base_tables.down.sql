@@ -0,0 +1,42 @@
+-- Migration: 001_create_base_tables.down.sql
+-- Description: Drops all base tables, functions and types created by 001_create_base_tables.sql
+-- Created: 2026-10-18
+
+-- ============================================================================
+-- TABLES (reverse dependency order; triggers are dropped with their tables)
+-- ============================================================================
+
+DROP TABLE IF EXISTS wishlist_items;
+DROP TABLE IF EXISTS wishlists;
+DROP TABLE IF EXISTS product_reviews;
+DROP TABLE IF EXISTS shipping_details;
+DROP TABLE IF EXISTS order_coupons;
+DROP TABLE IF EXISTS coupons;
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS customer_addresses;
+DROP TABLE IF EXISTS customers;
+DROP TABLE IF EXISTS inventory_movements;
+DROP TABLE IF EXISTS product_images;
+DROP TABLE IF EXISTS products;
+DROP TABLE IF EXISTS categories;
+
+-- ============================================================================
+-- FUNCTIONS
+-- ============================================================================
+
+DROP FUNCTION IF EXISTS update_product_rating();
+DROP FUNCTION IF EXISTS update_product_stock();
+DROP FUNCTION IF EXISTS update_updated_at_column();
+
+-- ============================================================================
+-- ENUMS
+-- ============================================================================
+
+DROP TYPE IF EXISTS product_status;
+DROP TYPE IF EXISTS payment_method;
+DROP TYPE IF EXISTS payment_status;
+DROP TYPE IF EXISTS order_status;
+
+-- Extensions (uuid-ossp, pg_trgm) are left installed; other schemas may use them
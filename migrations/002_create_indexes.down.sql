@@ -0,0 +1,65 @@
+-- Migration: 002_create_indexes.down.sql
+-- Description: Drops the indexes created by 002_create_indexes.sql
+-- Created: 2026-10-18
+
+DROP INDEX IF EXISTS idx_reviews_approved;
+DROP INDEX IF EXISTS idx_products_low_stock;
+DROP INDEX IF EXISTS idx_orders_pending;
+DROP INDEX IF EXISTS idx_products_active_only;
+DROP INDEX IF EXISTS idx_inventory_product_type_date;
+DROP INDEX IF EXISTS idx_order_items_order_product;
+DROP INDEX IF EXISTS idx_orders_customer_status;
+DROP INDEX IF EXISTS idx_products_status_price;
+DROP INDEX IF EXISTS idx_products_category_status_featured;
+DROP INDEX IF EXISTS idx_customer_addresses_deleted_at;
+DROP INDEX IF EXISTS idx_wishlist_items_deleted_at;
+DROP INDEX IF EXISTS idx_wishlists_deleted_at;
+DROP INDEX IF EXISTS idx_shipping_details_deleted_at;
+DROP INDEX IF EXISTS idx_product_reviews_deleted_at;
+DROP INDEX IF EXISTS idx_product_images_deleted_at;
+DROP INDEX IF EXISTS idx_coupons_deleted_at;
+DROP INDEX IF EXISTS idx_payments_deleted_at;
+DROP INDEX IF EXISTS idx_order_items_deleted_at;
+DROP INDEX IF EXISTS idx_orders_deleted_at;
+DROP INDEX IF EXISTS idx_customers_deleted_at;
+DROP INDEX IF EXISTS idx_products_deleted_at;
+DROP INDEX IF EXISTS idx_categories_deleted_at;
+DROP INDEX IF EXISTS idx_orders_status_date;
+DROP INDEX IF EXISTS idx_orders_customer_date;
+DROP INDEX IF EXISTS idx_customers_name;
+DROP INDEX IF EXISTS idx_products_views;
+DROP INDEX IF EXISTS idx_products_rating;
+DROP INDEX IF EXISTS idx_products_stock_low;
+DROP INDEX IF EXISTS idx_products_price;
+DROP INDEX IF EXISTS idx_products_description_trgm;
+DROP INDEX IF EXISTS idx_products_name_trgm;
+DROP INDEX IF EXISTS idx_wishlist_items_product_id;
+DROP INDEX IF EXISTS idx_wishlist_items_wishlist_id;
+DROP INDEX IF EXISTS idx_wishlists_customer_id;
+DROP INDEX IF EXISTS idx_product_reviews_rating;
+DROP INDEX IF EXISTS idx_product_reviews_customer_id;
+DROP INDEX IF EXISTS idx_product_reviews_product_id;
+DROP INDEX IF EXISTS idx_shipping_details_tracking_number;
+DROP INDEX IF EXISTS idx_shipping_details_order_id;
+DROP INDEX IF EXISTS idx_order_coupons_coupon_id;
+DROP INDEX IF EXISTS idx_order_coupons_order_id;
+DROP INDEX IF EXISTS idx_payments_transaction_id;
+DROP INDEX IF EXISTS idx_payments_status;
+DROP INDEX IF EXISTS idx_payments_order_id;
+DROP INDEX IF EXISTS idx_order_items_product_id;
+DROP INDEX IF EXISTS idx_order_items_order_id;
+DROP INDEX IF EXISTS idx_orders_order_number;
+DROP INDEX IF EXISTS idx_orders_created_at;
+DROP INDEX IF EXISTS idx_orders_status;
+DROP INDEX IF EXISTS idx_orders_customer_id;
+DROP INDEX IF EXISTS idx_customer_addresses_is_default;
+DROP INDEX IF EXISTS idx_customer_addresses_customer_id;
+DROP INDEX IF EXISTS idx_inventory_movements_created_at;
+DROP INDEX IF EXISTS idx_inventory_movements_reference;
+DROP INDEX IF EXISTS idx_inventory_movements_product_id;
+DROP INDEX IF EXISTS idx_product_images_is_primary;
+DROP INDEX IF EXISTS idx_product_images_product_id;
+DROP INDEX IF EXISTS idx_products_is_featured;
+DROP INDEX IF EXISTS idx_products_status;
+DROP INDEX IF EXISTS idx_products_category_id;
+DROP INDEX IF EXISTS idx_categories_parent_id;
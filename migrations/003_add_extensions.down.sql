@@ -0,0 +1,6 @@
+-- Migration: 003_add_extensions.down.sql
+-- Description: Drops the extensions added by 003_add_extensions.sql
+-- Created: 2026-10-18
+
+-- pg_trgm is owned by 001_create_base_tables.sql and is left in place
+DROP EXTENSION IF EXISTS btree_gin;
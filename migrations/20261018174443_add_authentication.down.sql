@@ -0,0 +1,8 @@
+-- Migration: 20261018174443_add_authentication.down.sql
+-- Description: Reverts 20261018174443_add_authentication.up.sql
+-- Created: 2026-10-18
+
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS staff_users;
+DROP INDEX IF EXISTS idx_customers_email_lower;
+ALTER TABLE customers DROP COLUMN IF EXISTS password_hash;
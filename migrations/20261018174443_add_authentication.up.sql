@@ -0,0 +1,60 @@
+-- Migration: 20261018174443_add_authentication.up.sql
+-- Description: Adds customer passwords, staff accounts and refresh tokens for JWT authentication
+-- Created: 2026-10-18
+
+-- ============================================================================
+-- CREDENTIALS
+-- ============================================================================
+
+-- PHC-formatted argon2id hash ($argon2id$v=19$...); bcrypt hashes ($2a$...)
+-- imported from elsewhere are accepted and upgraded on the next login.
+-- Customers without one cannot log in.
+ALTER TABLE customers ADD COLUMN password_hash VARCHAR(255);
+
+-- Logins look emails up case-insensitively
+CREATE INDEX idx_customers_email_lower ON customers(lower(email));
+
+-- Staff accounts (admins, catalog managers, ...); they are created with
+-- cmd/staff, never through the API
+CREATE TABLE staff_users (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    name VARCHAR(200) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_staff_users_updated_at
+    BEFORE UPDATE ON staff_users
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE UNIQUE INDEX idx_staff_users_email_lower ON staff_users(lower(email));
+
+-- ============================================================================
+-- REFRESH TOKENS
+-- ============================================================================
+
+-- Only the SHA-256 of each token is stored. Tokens issued by one login form
+-- a family: refreshing rotates the token (rotated_at) and issues the next
+-- one in the family. Presenting a rotated token again means it was stolen,
+-- so the whole family is revoked (revoked_at), as it is on logout.
+CREATE TABLE refresh_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    token_hash BYTEA NOT NULL UNIQUE,
+    family_id UUID NOT NULL,
+    customer_id BIGINT REFERENCES customers(id) ON DELETE CASCADE,
+    staff_user_id BIGINT REFERENCES staff_users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    rotated_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT refresh_tokens_one_owner CHECK (num_nonnulls(customer_id, staff_user_id) = 1)
+);
+
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id) WHERE revoked_at IS NULL;
+CREATE INDEX idx_refresh_tokens_customer_id ON refresh_tokens(customer_id) WHERE customer_id IS NOT NULL;
+CREATE INDEX idx_refresh_tokens_staff_user_id ON refresh_tokens(staff_user_id) WHERE staff_user_id IS NOT NULL;
@@ -0,0 +1,8 @@
+-- Migration: 20261018203012_add_roles_and_permissions.down.sql
+-- Description: Reverts 20261018203012_add_roles_and_permissions.up.sql
+-- Created: 2026-10-18
+
+DROP TABLE IF EXISTS staff_user_roles;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
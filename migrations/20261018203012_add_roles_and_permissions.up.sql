@@ -0,0 +1,92 @@
+-- Migration: 20261018203012_add_roles_and_permissions.up.sql
+-- Description: Adds roles and permissions for authorizing staff and customer operations
+-- Created: 2026-10-18
+
+-- ============================================================================
+-- ROLES AND PERMISSIONS
+-- ============================================================================
+
+-- Permission names are matched by the code (auth.Permission); roles grant
+-- them. Access tokens carry the permissions of the caller's roles when they
+-- were issued, so changes apply at the caller's next login or refresh.
+CREATE TABLE roles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    description TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE permissions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE role_permissions (
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission_id INTEGER NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+-- Customers all hold the customer role implicitly; only staff users are
+-- assigned roles
+CREATE TABLE staff_user_roles (
+    staff_user_id BIGINT NOT NULL REFERENCES staff_users(id) ON DELETE CASCADE,
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (staff_user_id, role_id)
+);
+
+CREATE INDEX idx_staff_user_roles_role_id ON staff_user_roles(role_id);
+
+-- ============================================================================
+-- SEED DATA
+-- ============================================================================
+
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'Every staff operation'),
+    ('catalog_manager', 'Maintains categories and products'),
+    ('warehouse', 'Adjusts stock and fulfils orders'),
+    ('support', 'Helps customers with their orders and refunds'),
+    ('customer', 'Held by every customer, for their own resources');
+
+INSERT INTO permissions (name, description) VALUES
+    ('catalog:write', 'Create, update and delete categories and products'),
+    ('stock:adjust', 'Change stock quantities'),
+    ('orders:read', 'Read any customer''s orders'),
+    ('orders:refund', 'Refund payments'),
+    ('customers:read', 'Read any customer''s account, addresses and wishlists'),
+    ('staff:manage', 'Manage staff users and their roles'),
+    ('orders:own', 'Place and read one''s own orders'),
+    ('addresses:own', 'Manage one''s own addresses'),
+    ('wishlists:own', 'Manage one''s own wishlists');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM (VALUES
+    ('admin', 'catalog:write'),
+    ('admin', 'stock:adjust'),
+    ('admin', 'orders:read'),
+    ('admin', 'orders:refund'),
+    ('admin', 'customers:read'),
+    ('admin', 'staff:manage'),
+    ('catalog_manager', 'catalog:write'),
+    ('warehouse', 'stock:adjust'),
+    ('warehouse', 'orders:read'),
+    ('support', 'orders:read'),
+    ('support', 'orders:refund'),
+    ('support', 'customers:read'),
+    ('customer', 'orders:own'),
+    ('customer', 'addresses:own'),
+    ('customer', 'wishlists:own')
+) AS grants(role_name, permission_name)
+JOIN roles r ON r.name = grants.role_name
+JOIN permissions p ON p.name = grants.permission_name;
+
+-- Staff users created before roles existed could do every staff operation;
+-- they keep that as admins
+INSERT INTO staff_user_roles (staff_user_id, role_id)
+SELECT s.id, r.id
+FROM staff_users s
+JOIN roles r ON r.name = 'admin';
@@ -0,0 +1,6 @@
+-- Migration: 20261018212241_make_customer_emails_unique.down.sql
+-- Description: Reverts 20261018212241_make_customer_emails_unique.up.sql
+-- Created: 2026-10-18
+
+DROP INDEX IF EXISTS idx_customers_email_lower;
+CREATE INDEX idx_customers_email_lower ON customers(lower(email));
@@ -0,0 +1,29 @@
+-- Migration: 20261018212241_make_customer_emails_unique.up.sql
+-- Description: Makes customer emails unique regardless of case
+-- Created: 2026-10-18
+
+-- Logins look emails up case-insensitively, so emails must be unique
+-- regardless of case. Existing duplicates cannot be merged automatically
+-- (orders and addresses hang off each row); stop and name them instead.
+DO $$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT string_agg(email_lower || ' (ids ' || ids || ')', ', ')
+    INTO duplicates
+    FROM (
+        SELECT lower(email) AS email_lower, string_agg(id::text, ', ' ORDER BY id) AS ids
+        FROM customers
+        GROUP BY lower(email)
+        HAVING count(*) > 1
+    ) d;
+
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'customers have emails that differ only in case: %', duplicates
+            USING HINT = 'Merge or rename these customers, then run the migration again';
+    END IF;
+END $$;
+
+-- Replaces the plain index added with authentication
+DROP INDEX IF EXISTS idx_customers_email_lower;
+CREATE UNIQUE INDEX idx_customers_email_lower ON customers(lower(email));
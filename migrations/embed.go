@@ -0,0 +1,25 @@
+// Package migrations embeds the SQL migration files so binaries can apply
+// them without a migrations directory on disk.
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+
+	"ecom/pkg/migrate"
+)
+
+// FS holds the top-level *.sql files of this directory. Subdirectories such
+// as legacy/ are not embedded.
+//
+//go:embed *.sql
+var FS embed.FS
+
+// NewRunner returns a migration runner over the embedded migrations, or over
+// dir on disk when dir is set.
+func NewRunner(db *sql.DB, dir string) *migrate.MigrationRunner {
+	if dir != "" {
+		return migrate.NewMigrationRunner(db, dir)
+	}
+	return migrate.NewMigrationRunnerFS(db, FS)
+}
@@ -0,0 +1,308 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// diffIgnoredTables are bookkeeping tables that are not part of the schema
+var diffIgnoredTables = map[string]bool{
+	"schema_migrations": true,
+	"seed_runs":         true,
+}
+
+var concurrentlyPattern = regexp.MustCompile(`(?i)\bCONCURRENTLY\b`)
+
+// Difference is a single schema object that differs between what the applied
+// migrations produce and what the live database contains
+type Difference struct {
+	Kind     string // table, column, type, index, trigger
+	Name     string
+	Change   string // missing, unexpected, differs
+	Expected string
+	Actual   string
+}
+
+// DriftReport is the result of comparing a live schema with its migrations
+type DriftReport struct {
+	Schema      string
+	Versions    []string
+	Differences []Difference
+}
+
+// HasDrift reports whether any difference was found
+func (r *DriftReport) HasDrift() bool {
+	return len(r.Differences) > 0
+}
+
+// Print writes the report in the same style as Status
+func (r *DriftReport) Print() {
+	fmt.Printf("\n🔍 Schema drift for %q (applied migrations: %s):\n", r.Schema, strings.Join(r.Versions, ", "))
+	fmt.Println(strings.Repeat("-", 60))
+
+	if !r.HasDrift() {
+		fmt.Println("✅ No drift: the database matches the applied migrations")
+		fmt.Println(strings.Repeat("-", 60))
+		return
+	}
+
+	for _, d := range r.Differences {
+		switch d.Change {
+		case "missing":
+			fmt.Printf("➖ %s %s is missing from the database\n", d.Kind, d.Name)
+		case "unexpected":
+			fmt.Printf("➕ %s %s exists in the database but not in the migrations\n", d.Kind, d.Name)
+		default:
+			fmt.Printf("✏️  %s %s differs\n", d.Kind, d.Name)
+			fmt.Printf("      expected: %s\n", d.Expected)
+			fmt.Printf("      actual:   %s\n", d.Actual)
+		}
+	}
+	fmt.Println(strings.Repeat("-", 60))
+	fmt.Printf("❌ %d difference(s) found\n", len(r.Differences))
+}
+
+// schemaSnapshot is the introspected shape of one schema. Keys are object
+// names, values a normalized definition used for comparison.
+type schemaSnapshot struct {
+	Tables   map[string]string
+	Columns  map[string]string
+	Types    map[string]string
+	Indexes  map[string]string
+	Triggers map[string]string
+}
+
+// Diff compares the live schema with the schema the applied migrations
+// should have produced. The expected schema is built by replaying the applied
+// up files into a scratch schema inside a transaction that is always rolled
+// back, so the database is left untouched.
+func (m *MigrationRunner) Diff(schema string) (*DriftReport, error) {
+	ctx := context.Background()
+
+	migrations, err := m.GetMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := m.tableExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[string]AppliedMigration)
+	if exists {
+		if applied, err = m.GetAppliedMigrations(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	scratch := fmt.Sprintf("migrate_diff_%d", time.Now().UnixNano())
+	if _, err := tx.ExecContext(ctx, "CREATE SCHEMA "+scratch); err != nil {
+		return nil, fmt.Errorf("failed to create scratch schema: %w", err)
+	}
+	// Extensions (pg_trgm operator classes etc.) stay resolvable through public
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL search_path TO %s, public", scratch)); err != nil {
+		return nil, fmt.Errorf("failed to set search_path: %w", err)
+	}
+
+	report := &DriftReport{Schema: schema}
+	for _, mig := range migrations {
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		report.Versions = append(report.Versions, mig.Version)
+
+		content, err := fs.ReadFile(m.fsys, mig.UpFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file %s: %w", mig.UpFile, err)
+		}
+
+		for i, stmt := range SplitStatements(string(content)) {
+			// CONCURRENTLY cannot run in the scratch transaction; the result is the same
+			query := concurrentlyPattern.ReplaceAllString(stmt.SQL, "")
+			if _, err := tx.ExecContext(ctx, query); err != nil {
+				return nil, fmt.Errorf("failed to build expected schema: %w", statementError(mig.UpFile, i+1, stmt, err))
+			}
+		}
+	}
+
+	expected, err := snapshotSchema(ctx, tx, scratch)
+	if err != nil {
+		return nil, err
+	}
+	actual, err := snapshotSchema(ctx, tx, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Differences = append(report.Differences, compareObjects("table", expected.Tables, actual.Tables)...)
+	report.Differences = append(report.Differences, compareObjects("type", expected.Types, actual.Types)...)
+	report.Differences = append(report.Differences, compareColumns(expected, actual)...)
+	report.Differences = append(report.Differences, compareObjects("index", expected.Indexes, actual.Indexes)...)
+	report.Differences = append(report.Differences, compareObjects("trigger", expected.Triggers, actual.Triggers)...)
+
+	return report, nil
+}
+
+// snapshotSchema introspects pg_catalog for a single schema
+func snapshotSchema(ctx context.Context, tx *sql.Tx, schema string) (*schemaSnapshot, error) {
+	snap := &schemaSnapshot{
+		Tables:   make(map[string]string),
+		Columns:  make(map[string]string),
+		Types:    make(map[string]string),
+		Indexes:  make(map[string]string),
+		Triggers: make(map[string]string),
+	}
+
+	// Qualified names differ between the scratch and live schema; strip them
+	normalize := func(def string) string {
+		def = strings.ReplaceAll(def, schema+".", "")
+		return strings.ReplaceAll(def, "public.", "")
+	}
+
+	queries := []struct {
+		name  string
+		query string
+		add   func(table, name, def string)
+	}{
+		{
+			name: "tables",
+			query: `
+				SELECT c.relname, '', ''
+				FROM pg_class c
+				JOIN pg_namespace n ON n.oid = c.relnamespace
+				WHERE n.nspname = $1 AND c.relkind IN ('r', 'p')`,
+			add: func(table, _, _ string) { snap.Tables[table] = table },
+		},
+		{
+			name: "columns",
+			query: `
+				SELECT c.relname, a.attname,
+				       format_type(a.atttypid, a.atttypmod)
+				       || CASE WHEN a.attnotnull THEN ' NOT NULL' ELSE '' END
+				       || COALESCE(' DEFAULT ' || pg_get_expr(d.adbin, d.adrelid), '')
+				FROM pg_attribute a
+				JOIN pg_class c ON c.oid = a.attrelid
+				JOIN pg_namespace n ON n.oid = c.relnamespace
+				LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+				WHERE n.nspname = $1 AND c.relkind IN ('r', 'p') AND a.attnum > 0 AND NOT a.attisdropped`,
+			add: func(table, name, def string) { snap.Columns[table+"."+name] = normalize(def) },
+		},
+		{
+			name: "types",
+			query: `
+				SELECT '', t.typname, string_agg(e.enumlabel, ', ' ORDER BY e.enumsortorder)
+				FROM pg_type t
+				JOIN pg_enum e ON e.enumtypid = t.oid
+				JOIN pg_namespace n ON n.oid = t.typnamespace
+				WHERE n.nspname = $1
+				GROUP BY t.typname`,
+			add: func(_, name, def string) { snap.Types[name] = "ENUM (" + def + ")" },
+		},
+		{
+			name: "indexes",
+			query: `
+				SELECT tablename, indexname, indexdef
+				FROM pg_indexes
+				WHERE schemaname = $1`,
+			add: func(table, name, def string) { snap.Indexes[name] = normalize(def) },
+		},
+		{
+			name: "triggers",
+			query: `
+				SELECT c.relname, t.tgname, pg_get_triggerdef(t.oid)
+				FROM pg_trigger t
+				JOIN pg_class c ON c.oid = t.tgrelid
+				JOIN pg_namespace n ON n.oid = c.relnamespace
+				WHERE n.nspname = $1 AND NOT t.tgisinternal`,
+			add: func(table, name, def string) { snap.Triggers[table+"."+name] = normalize(def) },
+		},
+	}
+
+	for _, q := range queries {
+		rows, err := tx.QueryContext(ctx, q.query, schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to introspect %s of schema %s: %w", q.name, schema, err)
+		}
+
+		for rows.Next() {
+			var table, name, def string
+			if err := rows.Scan(&table, &name, &def); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s of schema %s: %w", q.name, schema, err)
+			}
+			if diffIgnoredTables[table] {
+				continue
+			}
+			q.add(table, name, def)
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate %s of schema %s: %w", q.name, schema, err)
+		}
+	}
+
+	return snap, nil
+}
+
+// compareObjects diffs two name → definition maps
+func compareObjects(kind string, expected, actual map[string]string) []Difference {
+	var diffs []Difference
+
+	for _, name := range sortedKeys(expected) {
+		def, ok := actual[name]
+		switch {
+		case !ok:
+			diffs = append(diffs, Difference{Kind: kind, Name: name, Change: "missing", Expected: expected[name]})
+		case def != expected[name]:
+			diffs = append(diffs, Difference{Kind: kind, Name: name, Change: "differs", Expected: expected[name], Actual: def})
+		}
+	}
+
+	for _, name := range sortedKeys(actual) {
+		if _, ok := expected[name]; !ok {
+			diffs = append(diffs, Difference{Kind: kind, Name: name, Change: "unexpected", Actual: actual[name]})
+		}
+	}
+
+	return diffs
+}
+
+// compareColumns diffs columns of tables present on both sides; columns of
+// missing or unexpected tables are already covered by the table difference
+func compareColumns(expected, actual *schemaSnapshot) []Difference {
+	shared := func(columns map[string]string, other *schemaSnapshot) map[string]string {
+		result := make(map[string]string)
+		for key, def := range columns {
+			table, _, _ := strings.Cut(key, ".")
+			if _, ok := other.Tables[table]; ok {
+				result[key] = def
+			}
+		}
+		return result
+	}
+
+	return compareObjects("column", shared(expected.Columns, actual), shared(actual.Columns, expected))
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
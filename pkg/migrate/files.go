@@ -0,0 +1,159 @@
+package migrate
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migration is a versioned schema change made of an up file and an optional
+// down file. Single-file migrations (NNN_name.sql) are treated as up-only.
+type Migration struct {
+	Version  string
+	Name     string
+	UpFile   string
+	DownFile string
+}
+
+// HasDown reports whether the migration can be rolled back
+func (mig Migration) HasDown() bool {
+	return mig.DownFile != ""
+}
+
+// versionNumber returns the numeric value of the version used for ordering
+func (mig Migration) versionNumber() uint64 {
+	n, _ := strconv.ParseUint(mig.Version, 10, 64)
+	return n
+}
+
+var migrationNamePattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// parseMigrationFilename splits a migration filename into version, name and
+// direction ("up" or "down"). Plain .sql files are "up".
+func parseMigrationFilename(filename string) (version, name, direction string, err error) {
+	base := strings.TrimSuffix(filename, ".sql")
+	direction = "up"
+
+	switch {
+	case strings.HasSuffix(base, ".up"):
+		base = strings.TrimSuffix(base, ".up")
+	case strings.HasSuffix(base, ".down"):
+		base = strings.TrimSuffix(base, ".down")
+		direction = "down"
+	}
+
+	version, name, _ = strings.Cut(base, "_")
+	if _, err := strconv.ParseUint(version, 10, 64); err != nil {
+		return "", "", "", fmt.Errorf("migration file %s must start with a numeric version", filename)
+	}
+
+	return version, name, direction, nil
+}
+
+// loadMigrations reads the migrations directory and pairs up/down files by
+// version. It fails on duplicate versions and on down files without an up file.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	byVersion := make(map[string]*Migration)
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".sql") {
+			continue
+		}
+
+		version, name, direction, err := parseMigrationFilename(file.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: name}
+			byVersion[version] = mig
+		}
+
+		target := &mig.UpFile
+		if direction == "down" {
+			target = &mig.DownFile
+		}
+		if *target != "" {
+			return nil, fmt.Errorf("duplicate migration version %s: %s and %s", version, *target, file.Name())
+		}
+		if mig.Name != name {
+			return nil, fmt.Errorf("duplicate migration version %s: %s_%s and %s", version, version, mig.Name, file.Name())
+		}
+		*target = file.Name()
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.UpFile == "" {
+			return nil, fmt.Errorf("migration %s has a down file (%s) but no up file", mig.Version, mig.DownFile)
+		}
+		migrations = append(migrations, *mig)
+	}
+
+	// Sort numerically so 3-digit and timestamp versions order correctly
+	sort.Slice(migrations, func(i, j int) bool {
+		a, b := migrations[i].versionNumber(), migrations[j].versionNumber()
+		if a != b {
+			return a < b
+		}
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Create scaffolds a new timestamped up/down migration pair in dir and
+// returns the paths of the created files.
+func Create(dir, name string, now time.Time) (string, string, error) {
+	slug := strings.Trim(migrationNamePattern.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" {
+		return "", "", fmt.Errorf("migration name is required")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", fmt.Errorf("failed to create migrations directory: %w", err)
+	}
+
+	version := now.UTC().Format("20060102150405")
+	base := version + "_" + slug
+	upPath := filepath.Join(dir, base+".up.sql")
+	downPath := filepath.Join(dir, base+".down.sql")
+
+	header := func(filename, description string) string {
+		return fmt.Sprintf("-- Migration: %s\n-- Description: %s\n-- Created: %s\n\n",
+			filename, description, now.UTC().Format("2006-01-02"))
+	}
+
+	files := map[string]string{
+		upPath:   header(base+".up.sql", name),
+		downPath: header(base+".down.sql", "Reverts "+base+".up.sql"),
+	}
+
+	for path, content := range files {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to create migration file: %w", err)
+		}
+		if _, err := f.WriteString(content); err != nil {
+			f.Close()
+			return "", "", fmt.Errorf("failed to write migration file %s: %w", path, err)
+		}
+		if err := f.Close(); err != nil {
+			return "", "", fmt.Errorf("failed to write migration file %s: %w", path, err)
+		}
+	}
+
+	return upPath, downPath, nil
+}
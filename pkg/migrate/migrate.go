@@ -1,30 +1,64 @@
 package migrate
 
 import (
+	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"io/fs"
 	"log"
 	"os"
-	"path/filepath"
-	"sort"
+	"os/user"
+	"strconv"
 	"strings"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 // MigrationRunner handles database migrations
 type MigrationRunner struct {
-	db    *sql.DB
-	dir   string
-	table string
+	db        *sql.DB
+	fsys      fs.FS
+	table     string
+	appliedBy string
+	dryRun    bool
+	backfill  bool
 }
 
-// NewMigrationRunner creates a new migration runner
+// AppliedMigration is a row of the migrations tracking table
+type AppliedMigration struct {
+	Version         string
+	Filename        string
+	Checksum        string
+	ExecutionTimeMs int64
+	AppliedBy       string
+	AppliedAt       time.Time
+}
+
+// NewMigrationRunner creates a new migration runner reading from a directory on disk
 func NewMigrationRunner(db *sql.DB, migrationsDir string) *MigrationRunner {
+	return NewMigrationRunnerFS(db, os.DirFS(migrationsDir))
+}
+
+// NewMigrationRunnerFS creates a new migration runner reading from fsys,
+// e.g. the embedded migrations.FS
+func NewMigrationRunnerFS(db *sql.DB, fsys fs.FS) *MigrationRunner {
 	return &MigrationRunner{
-		db:    db,
-		dir:   migrationsDir,
-		table: "schema_migrations",
+		db:        db,
+		fsys:      fsys,
+		table:     "schema_migrations",
+		appliedBy: defaultAppliedBy(),
+	}
+}
+
+// SetAppliedBy overrides the identity recorded with each applied migration
+func (m *MigrationRunner) SetAppliedBy(name string) {
+	if name != "" {
+		m.appliedBy = name
 	}
 }
 
@@ -42,211 +76,731 @@ func (m *MigrationRunner) Init() error {
 		return fmt.Errorf("failed to create migrations table: %w", err)
 	}
 
+	// Tables created by older runners only have version and applied_at
+	alterQuery := fmt.Sprintf(`
+		ALTER TABLE %s
+			ADD COLUMN IF NOT EXISTS filename VARCHAR(255),
+			ADD COLUMN IF NOT EXISTS checksum VARCHAR(64),
+			ADD COLUMN IF NOT EXISTS execution_time_ms BIGINT,
+			ADD COLUMN IF NOT EXISTS applied_by VARCHAR(255)
+	`, m.table)
+
+	if _, err := m.db.Exec(alterQuery); err != nil {
+		return fmt.Errorf("failed to upgrade migrations table: %w", err)
+	}
+
 	log.Println("✅ Migrations table initialized")
 	return nil
 }
 
-// GetAppliedMigrations returns a map of applied migration versions
-func (m *MigrationRunner) GetAppliedMigrations() (map[string]bool, error) {
-	applied := make(map[string]bool)
+// Lock takes a Postgres session-level advisory lock so that concurrent
+// runners (e.g. two deploys starting at once) apply migrations one at a time.
+// The returned function releases the lock.
+func (m *MigrationRunner) Lock() (func(), error) {
+	ctx := context.Background()
 
-	query := fmt.Sprintf("SELECT version FROM %s ORDER BY version", m.table)
-	rows, err := m.db.Query(query)
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection for migration lock: %w", err)
+	}
+
+	key := m.lockKey()
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	if !acquired {
+		log.Println("⏳ Another migration is in progress, waiting for lock...")
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", key); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+	}
+
+	unlock := func() {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", key); err != nil {
+			log.Printf("⚠️  Failed to release migration lock: %v", err)
+		}
+		conn.Close()
+	}
+
+	return unlock, nil
+}
+
+// lockKey derives the advisory lock key from the tracking table name
+func (m *MigrationRunner) lockKey() int64 {
+	h := fnv.New64a()
+	h.Write([]byte("ecom:migrate:" + m.table))
+	return int64(h.Sum64())
+}
+
+// GetAppliedMigrations returns the applied migrations keyed by version
+func (m *MigrationRunner) GetAppliedMigrations(ctx context.Context) (map[string]AppliedMigration, error) {
+	applied := make(map[string]AppliedMigration)
+
+	query := fmt.Sprintf(`
+		SELECT version, COALESCE(filename, ''), COALESCE(checksum, ''),
+		       COALESCE(execution_time_ms, 0), COALESCE(applied_by, ''), applied_at
+		FROM %s
+		ORDER BY version
+	`, m.table)
+	rows, err := m.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var version string
-		if err := rows.Scan(&version); err != nil {
+		var a AppliedMigration
+		if err := rows.Scan(&a.Version, &a.Filename, &a.Checksum, &a.ExecutionTimeMs, &a.AppliedBy, &a.AppliedAt); err != nil {
 			return nil, err
 		}
-		applied[version] = true
+		applied[a.Version] = a
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate applied migrations: %w", err)
 	}
 
 	return applied, nil
 }
 
-// GetMigrationFiles returns sorted list of migration files
-func (m *MigrationRunner) GetMigrationFiles() ([]string, error) {
-	files, err := os.ReadDir(m.dir)
+// GetMigrations returns the migrations found in the migrations directory,
+// ordered by version. It fails if two files share the same version prefix.
+func (m *MigrationRunner) GetMigrations() ([]Migration, error) {
+	return loadMigrations(m.fsys)
+}
+
+// Checksum returns the SHA-256 checksum of a migration file
+func (m *MigrationRunner) Checksum(filename string) (string, error) {
+	content, err := fs.ReadFile(m.fsys, filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+		return "", fmt.Errorf("failed to read migration file %s: %w", filename, err)
 	}
+	return checksumOf(content), nil
+}
 
-	var migrations []string
-	for _, file := range files {
-		if file.IsDir() {
+// Verify checks that applied migration files have not been edited since
+// they were applied. Records without a checksum were made before checksums
+// were tracked, when the runner did not record the filename either, so
+// nothing shows they were applied from the current file (version 001 used
+// to be legacy/001_initial_schema.sql). Verify refuses them unless
+// SetBackfillChecksums is set, in which case the current file's checksum and
+// name are recorded.
+func (m *MigrationRunner) Verify(migrations []Migration, applied map[string]AppliedMigration) error {
+	var modified, unverified []string
+
+	for _, mig := range migrations {
+		record, ok := applied[mig.Version]
+		if !ok {
 			continue
 		}
-		if strings.HasSuffix(file.Name(), ".sql") {
-			migrations = append(migrations, file.Name())
+
+		checksum, err := m.Checksum(mig.UpFile)
+		if err != nil {
+			return err
+		}
+
+		if record.Checksum == "" {
+			if record.Filename != mig.UpFile && !m.backfill {
+				if record.Filename == "" {
+					unverified = append(unverified, mig.UpFile)
+				} else {
+					unverified = append(unverified, fmt.Sprintf("%s (applied as %s)", mig.UpFile, record.Filename))
+				}
+				continue
+			}
+			if m.dryRun {
+				continue
+			}
+			query := fmt.Sprintf("UPDATE %s SET checksum = $1, filename = $2 WHERE version = $3", m.table)
+			if _, err := m.db.Exec(query, checksum, mig.UpFile, mig.Version); err != nil {
+				return fmt.Errorf("failed to backfill checksum for %s: %w", mig.UpFile, err)
+			}
+			log.Printf("🔏 Recorded checksum for previously applied migration: %s", mig.UpFile)
+			continue
+		}
+
+		if record.Checksum != checksum {
+			modified = append(modified, mig.UpFile)
 		}
 	}
 
-	// Sort by filename (which should be numbered)
-	sort.Strings(migrations)
-	return migrations, nil
-}
+	if len(modified) > 0 {
+		return fmt.Errorf("applied migrations have been modified since they were run: %s", strings.Join(modified, ", "))
+	}
+
+	if len(unverified) > 0 {
+		return fmt.Errorf("applied migrations have no recorded checksum and cannot be verified: %s; "+
+			"check the schema with -command=diff, then rerun with -backfill-checksums to record the current files",
+			strings.Join(unverified, ", "))
+	}
 
-// RunMigration executes a single migration file
-func (m *MigrationRunner) RunMigration(filename string) error {
-	filepath := filepath.Join(m.dir, filename)
+	return nil
+}
 
+// RunMigration applies the up file of a migration and records it
+func (m *MigrationRunner) RunMigration(mig Migration) error {
 	// Read migration file
-	content, err := os.ReadFile(filepath)
+	content, err := fs.ReadFile(m.fsys, mig.UpFile)
 	if err != nil {
-		return fmt.Errorf("failed to read migration file %s: %w", filename, err)
+		return fmt.Errorf("failed to read migration file %s: %w", mig.UpFile, err)
+	}
+
+	if m.dryRun {
+		printPlan("apply", mig.UpFile, content)
+		return nil
 	}
 
-	// Extract version from filename (e.g., "001_create_tables.sql" -> "001")
-	version := strings.Split(filename, "_")[0]
+	// Record migration
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %s (version, filename, checksum, execution_time_ms, applied_by)
+		VALUES ($1, $2, $3, $4, $5)
+	`, m.table)
 
-	// Start transaction
-	tx, err := m.db.Begin()
+	elapsed, err := m.execute(mig.UpFile, content, func(ctx context.Context, exec execer, elapsed time.Duration) error {
+		if _, err := exec.ExecContext(ctx, insertQuery, mig.Version, mig.UpFile, checksumOf(content), elapsed.Milliseconds(), m.appliedBy); err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", mig.Version, err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return err
 	}
-	defer tx.Rollback()
 
-	// Execute migration
-	if _, err := tx.Exec(string(content)); err != nil {
-		return fmt.Errorf("failed to execute migration %s: %w", filename, err)
+	log.Printf("✅ Applied migration: %s (%v)", mig.UpFile, elapsed.Round(time.Millisecond))
+	return nil
+}
+
+// RollbackMigration applies the down file of a migration and removes its record
+func (m *MigrationRunner) RollbackMigration(mig Migration) error {
+	if !mig.HasDown() {
+		return fmt.Errorf("migration %s has no down file; create %s_%s.down.sql to roll it back", mig.UpFile, mig.Version, mig.Name)
 	}
 
-	// Record migration
-	insertQuery := fmt.Sprintf(
-		"INSERT INTO %s (version) VALUES ($1) ON CONFLICT (version) DO NOTHING",
-		m.table,
-	)
-	if _, err := tx.Exec(insertQuery, version); err != nil {
-		return fmt.Errorf("failed to record migration %s: %w", version, err)
+	content, err := fs.ReadFile(m.fsys, mig.DownFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file %s: %w", mig.DownFile, err)
+	}
+
+	if m.dryRun {
+		printPlan("roll back", mig.DownFile, content)
+		return nil
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE version = $1", m.table)
+
+	elapsed, err := m.execute(mig.DownFile, content, func(ctx context.Context, exec execer, _ time.Duration) error {
+		if _, err := exec.ExecContext(ctx, query, mig.Version); err != nil {
+			return fmt.Errorf("failed to remove migration record %s: %w", mig.Version, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("↩️  Rolled back migration: %s (%v)", mig.DownFile, elapsed.Round(time.Millisecond))
+	return nil
+}
+
+// execer is satisfied by both *sql.Tx and *sql.Conn
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// execute runs the statements of a migration file one at a time and then
+// calls record with the same transaction (or connection, for files marked
+// with the no-transaction directive). Errors name the failing statement and
+// its line number in the file.
+func (m *MigrationRunner) execute(filename string, content []byte, record func(context.Context, execer, time.Duration) error) (time.Duration, error) {
+	ctx := context.Background()
+	statements := SplitStatements(string(content))
+
+	run := func(exec execer) (time.Duration, error) {
+		start := time.Now()
+		for i, stmt := range statements {
+			if _, err := exec.ExecContext(ctx, stmt.SQL); err != nil {
+				return 0, statementError(filename, i+1, stmt, err)
+			}
+		}
+		elapsed := time.Since(start)
+		return elapsed, record(ctx, exec, elapsed)
+	}
+
+	if !isTransactional(string(content)) {
+		// A dedicated connection keeps session state (SET ...) between statements
+		conn, err := m.db.Conn(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to acquire connection: %w", err)
+		}
+		defer conn.Close()
+
+		log.Printf("⚠️  Running %s without a transaction", filename)
+		return run(conn)
+	}
+
+	// Start transaction
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	elapsed, err := run(tx)
+	if err != nil {
+		return 0, err
 	}
 
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit migration %s: %w", version, err)
+		return 0, fmt.Errorf("failed to commit %s: %w", filename, err)
 	}
 
-	log.Printf("✅ Applied migration: %s", filename)
-	return nil
+	return elapsed, nil
 }
 
-// Up runs all pending migrations
-func (m *MigrationRunner) Up() error {
+// statementError reports the failing statement with its line in the file.
+// Postgres errors carry a character position that narrows it to the exact line.
+func statementError(filename string, index int, stmt Statement, err error) error {
+	line := stmt.Line
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Position != "" {
+		if pos, convErr := strconv.Atoi(pqErr.Position); convErr == nil && pos > 0 {
+			runes := []rune(stmt.SQL)
+			if pos > len(runes) {
+				pos = len(runes)
+			}
+			line += strings.Count(string(runes[:pos-1]), "\n")
+		}
+	}
+
+	snippet, _, _ := strings.Cut(stmt.SQL, "\n")
+	if len(snippet) > 120 {
+		snippet = snippet[:120] + "..."
+	}
+
+	return fmt.Errorf("failed to execute %s at line %d (statement %d: %s): %w", filename, line, index, snippet, err)
+}
+
+// printPlan prints what a dry run would execute
+func printPlan(action, filename string, content []byte) {
+	mode := "in a transaction"
+	if !isTransactional(string(content)) {
+		mode = "WITHOUT a transaction"
+	}
+
+	fmt.Printf("\n📋 [dry-run] Would %s %s (%d statements, %s)\n", action, filename, len(SplitStatements(string(content))), mode)
+	fmt.Println(strings.Repeat("-", 60))
+	fmt.Println(strings.TrimRight(string(content), "\n"))
+	fmt.Println(strings.Repeat("-", 60))
+}
+
+// SetDryRun makes the runner print the migrations it would run, with their
+// contents, instead of executing them
+func (m *MigrationRunner) SetDryRun(dryRun bool) {
+	m.dryRun = dryRun
+}
+
+// SetBackfillChecksums lets Verify record the current file's checksum and
+// name for applied migrations that have no checksum
+func (m *MigrationRunner) SetBackfillChecksums(backfill bool) {
+	m.backfill = backfill
+}
+
+// prepare loads migrations, takes the migration lock, initializes the
+// tracking table and verifies checksums. Callers must invoke the returned
+// unlock function.
+func (m *MigrationRunner) prepare() ([]Migration, map[string]AppliedMigration, func(), error) {
+	// Get all migrations (fails fast on duplicate versions)
+	migrations, err := m.GetMigrations()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// A dry run only reads: no lock, no tracking table, no checksum backfill
+	if m.dryRun {
+		applied := make(map[string]AppliedMigration)
+		exists, err := m.tableExists(context.Background())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if exists {
+			if applied, err = m.GetAppliedMigrations(context.Background()); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		if err := m.Verify(migrations, applied); err != nil {
+			return nil, nil, nil, err
+		}
+		return migrations, applied, func() {}, nil
+	}
+
+	unlock, err := m.Lock()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	// Initialize migrations table
 	if err := m.Init(); err != nil {
-		return err
+		unlock()
+		return nil, nil, nil, err
 	}
 
-	// Get applied migrations
-	applied, err := m.GetAppliedMigrations()
+	// Get applied migrations (read under the lock so a concurrent run is seen)
+	applied, err := m.GetAppliedMigrations(context.Background())
 	if err != nil {
-		return err
+		unlock()
+		return nil, nil, nil, err
 	}
 
-	// Get all migration files
-	files, err := m.GetMigrationFiles()
+	if err := m.Verify(migrations, applied); err != nil {
+		unlock()
+		return nil, nil, nil, err
+	}
+
+	return migrations, applied, unlock, nil
+}
+
+// Up runs all pending migrations
+func (m *MigrationRunner) Up() error {
+	migrations, applied, unlock, err := m.prepare()
 	if err != nil {
 		return err
 	}
+	defer unlock()
+
+	if err := m.up(migrations, applied, ""); err != nil {
+		return err
+	}
 
-	// Run pending migrations
-	for _, file := range files {
-		version := strings.Split(file, "_")[0]
+	if m.dryRun {
+		log.Println("📋 Dry run complete, no changes were made")
+		return nil
+	}
 
-		if applied[version] {
-			log.Printf("⏭️  Skipping already applied migration: %s", file)
-			continue
+	log.Println("✅ All migrations applied successfully")
+	return nil
+}
+
+// up applies pending migrations in order, stopping after target if set
+func (m *MigrationRunner) up(migrations []Migration, applied map[string]AppliedMigration, target string) error {
+	for _, mig := range migrations {
+		if _, ok := applied[mig.Version]; ok {
+			log.Printf("⏭️  Skipping already applied migration: %s", mig.UpFile)
+		} else if err := m.RunMigration(mig); err != nil {
+			return fmt.Errorf("migration failed at %s: %w", mig.UpFile, err)
 		}
 
-		if err := m.RunMigration(file); err != nil {
-			return fmt.Errorf("migration failed at %s: %w", file, err)
+		if mig.Version == target {
+			break
 		}
 	}
-
-	log.Println("✅ All migrations applied successfully")
 	return nil
 }
 
-// Status shows migration status
-func (m *MigrationRunner) Status() error {
-	// Initialize if needed
-	if err := m.Init(); err != nil {
-		return err
+// appliedInReverse returns the applied migrations, newest version first
+func appliedInReverse(migrations []Migration, applied map[string]AppliedMigration) []Migration {
+	var result []Migration
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if _, ok := applied[migrations[i].Version]; ok {
+			result = append(result, migrations[i])
+		}
 	}
+	return result
+}
 
-	applied, err := m.GetAppliedMigrations()
+// Down rolls back the last steps applied migrations using their down files
+func (m *MigrationRunner) Down(steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("steps must be at least 1")
+	}
+
+	migrations, applied, unlock, err := m.prepare()
 	if err != nil {
 		return err
 	}
+	defer unlock()
+
+	toRollback := appliedInReverse(migrations, applied)
+	if len(toRollback) == 0 {
+		log.Println("ℹ️  No migrations to rollback")
+		return nil
+	}
+	if steps < len(toRollback) {
+		toRollback = toRollback[:steps]
+	}
+
+	return m.rollback(toRollback)
+}
+
+// rollback checks every migration can be reverted before reverting any
+func (m *MigrationRunner) rollback(toRollback []Migration) error {
+	for _, mig := range toRollback {
+		if !mig.HasDown() {
+			return fmt.Errorf("cannot roll back %s: no down file", mig.UpFile)
+		}
+	}
+
+	for _, mig := range toRollback {
+		if err := m.RollbackMigration(mig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	files, err := m.GetMigrationFiles()
+// Goto migrates up or down until version is the latest applied migration.
+// Version "0" rolls back every migration.
+func (m *MigrationRunner) Goto(version string) error {
+	migrations, applied, unlock, err := m.prepare()
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	fmt.Println("\n📊 Migration Status:")
-	fmt.Println(strings.Repeat("-", 60))
+	target, err := strconv.ParseUint(version, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid version %q", version)
+	}
 
-	for _, file := range files {
-		version := strings.Split(file, "_")[0]
-		if applied[version] {
-			fmt.Printf("✅ %s (applied)\n", file)
-		} else {
-			fmt.Printf("⏳ %s (pending)\n", file)
+	// Versions compare numerically, as they are ordered, so 1 finds 001
+	if target != 0 {
+		found := false
+		for _, mig := range migrations {
+			if mig.versionNumber() == target {
+				version = mig.Version
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no migration with version %s", version)
 		}
 	}
-	fmt.Println(strings.Repeat("-", 60))
 
+	// Roll back everything newer than the target
+	var toRollback []Migration
+	for _, mig := range appliedInReverse(migrations, applied) {
+		if mig.versionNumber() > target {
+			toRollback = append(toRollback, mig)
+		}
+	}
+	if err := m.rollback(toRollback); err != nil {
+		return err
+	}
+
+	if target != 0 {
+		if err := m.up(migrations, applied, version); err != nil {
+			return err
+		}
+	}
+
+	if m.dryRun {
+		log.Println("📋 Dry run complete, no changes were made")
+		return nil
+	}
+
+	log.Printf("✅ Database is at version %s", version)
 	return nil
 }
 
-// Down rolls back the last migration (simple implementation)
-// Note: For production, you'd want proper rollback migrations
-func (m *MigrationRunner) Down() error {
-	applied, err := m.GetAppliedMigrations()
+// Redo rolls back the last applied migration and applies it again
+func (m *MigrationRunner) Redo() error {
+	migrations, applied, unlock, err := m.prepare()
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	if len(applied) == 0 {
-		log.Println("ℹ️  No migrations to rollback")
+	toRollback := appliedInReverse(migrations, applied)
+	if len(toRollback) == 0 {
+		log.Println("ℹ️  No migrations to redo")
 		return nil
 	}
 
-	// Get all files
-	files, err := m.GetMigrationFiles()
-	if err != nil {
+	last := toRollback[0]
+	if err := m.rollback([]Migration{last}); err != nil {
 		return err
 	}
+	return m.RunMigration(last)
+}
 
-	// Find last applied migration
-	var lastFile string
-	for i := len(files) - 1; i >= 0; i-- {
-		version := strings.Split(files[i], "_")[0]
-		if applied[version] {
-			lastFile = files[i]
-			break
+// Pending returns the migrations that have not been applied yet. Unlike Up
+// it neither creates nor upgrades the tracking table, so it is safe for
+// read-only probes and works on tables made by older runners; its queries
+// are cancelled with ctx.
+func (m *MigrationRunner) Pending(ctx context.Context) ([]Migration, error) {
+	migrations, err := m.GetMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := m.tableExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return migrations, nil
+	}
+
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, mig := range migrations {
+		if _, ok := applied[mig.Version]; !ok {
+			pending = append(pending, mig)
 		}
 	}
+	return pending, nil
+}
 
-	if lastFile == "" {
-		log.Println("ℹ️  No migrations to rollback")
-		return nil
+// Version returns the highest applied migration version, or 0 when none
+// has been applied. Like Pending it does not create the tracking table.
+func (m *MigrationRunner) Version() (uint64, error) {
+	exists, err := m.tableExists(context.Background())
+	if err != nil || !exists {
+		return 0, err
 	}
 
-	version := strings.Split(lastFile, "_")[0]
+	applied, err := m.appliedVersions(context.Background())
+	if err != nil {
+		return 0, err
+	}
 
-	// Remove from tracking (actual rollback would require rollback SQL files)
-	query := fmt.Sprintf("DELETE FROM %s WHERE version = $1", m.table)
-	if _, err := m.db.Exec(query, version); err != nil {
-		return fmt.Errorf("failed to remove migration record: %w", err)
+	var version uint64
+	for v := range applied {
+		if n := (Migration{Version: v}).versionNumber(); n > version {
+			version = n
+		}
+	}
+	return version, nil
+}
+
+// appliedVersions returns the applied versions. It reads the version column
+// only, which tracking tables of every runner version have.
+func (m *MigrationRunner) appliedVersions(ctx context.Context) (map[string]bool, error) {
+	rows, err := m.db.QueryContext(ctx, fmt.Sprintf("SELECT version FROM %s", m.table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
 	}
+	defer rows.Close()
 
-	log.Printf("⚠️  Removed migration record: %s", lastFile)
-	log.Println("⚠️  Note: This only removes the record. Manual rollback may be needed.")
+	applied := make(map[string]bool)
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate applied migrations: %w", err)
+	}
+
+	return applied, nil
+}
+
+// tableExists reports whether the migrations tracking table has been created
+func (m *MigrationRunner) tableExists(ctx context.Context) (bool, error) {
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", m.table).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check migrations table: %w", err)
+	}
+	return exists, nil
+}
+
+// Status shows migration status
+func (m *MigrationRunner) Status() error {
+	// Initialize if needed
+	if err := m.Init(); err != nil {
+		return err
+	}
+
+	applied, err := m.GetAppliedMigrations(context.Background())
+	if err != nil {
+		return err
+	}
+
+	migrations, err := m.GetMigrations()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n📊 Migration Status:")
+	fmt.Println(strings.Repeat("-", 60))
+
+	known := make(map[string]bool)
+	for _, mig := range migrations {
+		known[mig.Version] = true
+
+		record, ok := applied[mig.Version]
+		if !ok {
+			fmt.Printf("⏳ %s (pending)\n", mig.UpFile)
+			continue
+		}
+
+		checksum, err := m.Checksum(mig.UpFile)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case record.Checksum != "" && record.Checksum != checksum:
+			fmt.Printf("❌ %s (applied, MODIFIED since %s)\n", mig.UpFile, record.AppliedAt.Format(time.RFC3339))
+		case record.AppliedBy != "":
+			fmt.Printf("✅ %s (applied %s by %s in %dms)\n", mig.UpFile, record.AppliedAt.Format(time.RFC3339), record.AppliedBy, record.ExecutionTimeMs)
+		default:
+			fmt.Printf("✅ %s (applied %s)\n", mig.UpFile, record.AppliedAt.Format(time.RFC3339))
+		}
+	}
+
+	for version, record := range applied {
+		if !known[version] {
+			fmt.Printf("⚠️  %s (applied, file missing)\n", firstNonEmpty(record.Filename, version))
+		}
+	}
+	fmt.Println(strings.Repeat("-", 60))
 
 	return nil
 }
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// checksumOf returns the hex-encoded SHA-256 of migration content
+func checksumOf(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// defaultAppliedBy identifies who is running migrations as user@host
+func defaultAppliedBy() string {
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		name = u.Username
+	}
+	if name == "" {
+		name = "unknown"
+	}
+
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return name
+	}
+	return name + "@" + host
+}
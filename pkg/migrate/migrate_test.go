@@ -0,0 +1,124 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"ecom/internal/testutil/pgtest"
+	"ecom/migrations"
+	"ecom/pkg/migrate"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(pgtest.RunMain(m))
+}
+
+var testFS = fstest.MapFS{
+	"001_create_base_tables.sql":    {Data: []byte("CREATE TABLE a (id INT);")},
+	"002_create_indexes.sql":        {Data: []byte("CREATE INDEX ON a (id);")},
+	"legacy/001_initial_schema.sql": {Data: []byte("CREATE TABLE invoices (id INT);")},
+}
+
+// toBaselineShape turns the tracking table into the one the first runner
+// made, with version and applied_at only
+func toBaselineShape(t *testing.T, db *sql.DB) {
+	t.Helper()
+	_, err := db.Exec(`
+		ALTER TABLE schema_migrations
+			DROP COLUMN filename,
+			DROP COLUMN checksum,
+			DROP COLUMN execution_time_ms,
+			DROP COLUMN applied_by
+	`)
+	if err != nil {
+		t.Fatalf("reshape schema_migrations: %v", err)
+	}
+}
+
+func TestVerifyRefusesRowsWithoutChecksum(t *testing.T) {
+	runner := migrate.NewMigrationRunnerFS(nil, testFS)
+	runner.SetDryRun(true) // nothing is written, so no database is needed
+
+	migs, err := runner.GetMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checksum, err := runner.Checksum("002_create_indexes.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 001 as the baseline runner recorded it, 002 as the current one does
+	applied := map[string]migrate.AppliedMigration{
+		"001": {Version: "001"},
+		"002": {Version: "002", Filename: "002_create_indexes.sql", Checksum: checksum},
+	}
+
+	err = runner.Verify(migs, applied)
+	if err == nil || !strings.Contains(err.Error(), "001_create_base_tables.sql") || !strings.Contains(err.Error(), "-backfill-checksums") {
+		t.Fatalf("Verify without -backfill-checksums = %v, want an error naming 001_create_base_tables.sql", err)
+	}
+
+	runner.SetBackfillChecksums(true)
+	if err := runner.Verify(migs, applied); err != nil {
+		t.Fatalf("Verify with -backfill-checksums: %v", err)
+	}
+}
+
+func TestUpBackfillsBaselineRowsOnlyWhenAsked(t *testing.T) {
+	db := pgtest.NewDB(t)
+	toBaselineShape(t, db)
+
+	err := migrations.NewRunner(db, "").Up()
+	if err == nil || !strings.Contains(err.Error(), "cannot be verified") {
+		t.Fatalf("Up on baseline rows = %v, want an unverified-checksum error", err)
+	}
+
+	var missing int
+	if err := db.QueryRow(`SELECT count(*) FROM schema_migrations WHERE checksum IS NULL`).Scan(&missing); err != nil {
+		t.Fatal(err)
+	}
+	if missing == 0 {
+		t.Fatal("Up without -backfill-checksums recorded checksums")
+	}
+
+	runner := migrations.NewRunner(db, "")
+	runner.SetBackfillChecksums(true)
+	if err := runner.Up(); err != nil {
+		t.Fatalf("Up with -backfill-checksums: %v", err)
+	}
+	if err := db.QueryRow(`SELECT count(*) FROM schema_migrations WHERE checksum IS NULL OR filename IS NULL`).Scan(&missing); err != nil {
+		t.Fatal(err)
+	}
+	if missing != 0 {
+		t.Fatalf("%d rows still have no checksum or filename", missing)
+	}
+}
+
+func TestPendingReadsBaselineTable(t *testing.T) {
+	db := pgtest.NewDB(t)
+	toBaselineShape(t, db)
+
+	pending, err := migrations.NewRunner(db, "").Pending(context.Background())
+	if err != nil {
+		t.Fatalf("Pending on a baseline table: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("Pending = %v, want none", pending)
+	}
+}
+
+func TestGotoComparesVersionsNumerically(t *testing.T) {
+	db := pgtest.NewDB(t)
+
+	// A dry run only prints the plan, so the test schema is left as it is
+	runner := migrations.NewRunner(db, "")
+	runner.SetDryRun(true)
+	if err := runner.Goto("3"); err != nil {
+		t.Fatalf("Goto(3): %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package migrate
+
+import (
+	"bufio"
+	"strings"
+)
+
+// noTransactionDirective in a migration's leading comment block makes the
+// runner execute it outside a transaction (needed for statements such as
+// CREATE INDEX CONCURRENTLY).
+const noTransactionDirective = "migrate:no-transaction"
+
+// Statement is a single SQL statement and the 1-based line it starts on
+type Statement struct {
+	SQL  string
+	Line int
+}
+
+// isTransactional reports whether the migration should run in a transaction.
+// Only the comment lines at the top of the file are inspected.
+func isTransactional(content string) bool {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "--") {
+			break
+		}
+		if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(line, "--")), noTransactionDirective) {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitStatements splits SQL into statements on semicolons, ignoring
+// semicolons inside quoted strings, quoted identifiers, dollar-quoted bodies
+// and comments. Statements that contain only comments are dropped.
+func SplitStatements(content string) []Statement {
+	var (
+		statements []Statement
+		current    strings.Builder
+		line       = 1
+		startLine  = 1
+		hasCode    bool
+	)
+
+	src := []rune(content)
+	n := len(src)
+
+	flush := func() {
+		if hasCode {
+			statements = append(statements, Statement{SQL: strings.TrimSpace(current.String()), Line: startLine})
+		}
+		current.Reset()
+		hasCode = false
+	}
+
+	// write appends runes to the current statement, tracking line numbers
+	write := func(rs []rune) {
+		for _, r := range rs {
+			if r == '\n' {
+				line++
+			}
+		}
+		current.WriteString(string(rs))
+	}
+
+	// markCode records the start of a statement, discarding leading comments
+	markCode := func() {
+		if !hasCode {
+			current.Reset()
+			hasCode = true
+			startLine = line
+		}
+	}
+
+	for i := 0; i < n; {
+		r := src[i]
+
+		switch {
+		// Line comment
+		case r == '-' && i+1 < n && src[i+1] == '-':
+			end := i
+			for end < n && src[end] != '\n' {
+				end++
+			}
+			write(src[i:end])
+			i = end
+
+		// Block comment (Postgres allows nesting)
+		case r == '/' && i+1 < n && src[i+1] == '*':
+			depth, end := 0, i
+			for end < n {
+				if end+1 < n && src[end] == '/' && src[end+1] == '*' {
+					depth++
+					end += 2
+				} else if end+1 < n && src[end] == '*' && src[end+1] == '/' {
+					depth--
+					end += 2
+					if depth == 0 {
+						break
+					}
+				} else {
+					end++
+				}
+			}
+			write(src[i:end])
+			i = end
+
+		// String literal or quoted identifier
+		case r == '\'' || r == '"':
+			markCode()
+			escapes := r == '\'' && i > 0 && (src[i-1] == 'E' || src[i-1] == 'e')
+			end := i + 1
+			for end < n {
+				if escapes && src[end] == '\\' {
+					end += 2
+					continue
+				}
+				if src[end] == r {
+					// A doubled quote is an escaped quote
+					if end+1 < n && src[end+1] == r {
+						end += 2
+						continue
+					}
+					end++
+					break
+				}
+				end++
+			}
+			if end > n {
+				end = n
+			}
+			write(src[i:end])
+			i = end
+
+		// Dollar-quoted string ($$...$$ or $tag$...$tag$)
+		case r == '$' && !(i > 0 && isIdentRune(src[i-1])):
+			tag, ok := dollarTag(src, i)
+			if !ok {
+				markCode()
+				write(src[i : i+1])
+				i++
+				continue
+			}
+			markCode()
+			end := n
+			for j := i + len(tag); j+len(tag) <= n; j++ {
+				if string(src[j:j+len(tag)]) == string(tag) {
+					end = j + len(tag)
+					break
+				}
+			}
+			write(src[i:end])
+			i = end
+
+		case r == ';':
+			write(src[i : i+1])
+			i++
+			flush()
+
+		default:
+			if r != ' ' && r != '\t' && r != '\n' && r != '\r' {
+				markCode()
+			}
+			write(src[i : i+1])
+			i++
+		}
+	}
+
+	flush()
+	return statements
+}
+
+// dollarTag returns the opening dollar-quote tag starting at src[i]
+func dollarTag(src []rune, i int) ([]rune, bool) {
+	end := i + 1
+	for end < len(src) && src[end] != '$' {
+		if !isIdentRune(src[end]) || (end == i+1 && src[end] >= '0' && src[end] <= '9') {
+			return nil, false
+		}
+		end++
+	}
+	if end >= len(src) {
+		return nil, false
+	}
+	return src[i : end+1], true
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r > 127
+}
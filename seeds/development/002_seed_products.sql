@@ -1,8 +1,6 @@
 -- Seed: 002_seed_products.sql
 -- Description: Seed products data
 
-BEGIN;
-
 INSERT INTO products (sku, name, slug, description, short_description, category_id, status, price, compare_at_price, cost_price, stock_quantity, low_stock_threshold, weight_kg, dimensions_cm, brand, manufacturer, is_featured, rating_average, rating_count, created_at, updated_at)
 VALUES
     (
@@ -144,5 +142,3 @@ VALUES
         CURRENT_TIMESTAMP
     )
 ON CONFLICT (sku) DO NOTHING;
-
-COMMIT;
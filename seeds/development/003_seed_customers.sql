@@ -1,8 +1,6 @@
 -- Seed: 003_seed_customers.sql
 -- Description: Seed customers data
 
-BEGIN;
-
 INSERT INTO customers (email, first_name, last_name, phone, is_active, created_at, updated_at)
 VALUES
     ('john.doe@example.com', 'John', 'Doe', '+1-555-0101', true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
@@ -11,5 +9,3 @@ VALUES
     ('alice.williams@example.com', 'Alice', 'Williams', '+1-555-0104', true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
     ('charlie.brown@example.com', 'Charlie', 'Brown', '+1-555-0105', true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 ON CONFLICT (email) DO NOTHING;
-
-COMMIT;